@@ -3,32 +3,93 @@ package config
 import (
 	"github.com/joho/godotenv"
 	"os"
+	"strconv"
 	"sync"
+	"time"
 )
 
 type AppConfig struct {
 	IsBusy               bool
 	isBanned             bool
-	Queue                []string
 	RedisHistoryAddress  string
 	RedisHistoryPassword string
 	RedisCookiesAddress  string
 	RedisCookiesPassword string
 	DefaultLogin         string
 	DefaultPassword      string
+	InstagramAccounts    string
+	AccountRotateEvery   int
+	SessionRefresh       time.Duration
+	StoryFetcher         string
+	FixturesDir          string
+	RecordFixtures       string
 	OpenAiKey            string
 	GeminiKey            string
+	ImageSummarizer      string
+	VideoSummarizer      string
+	MergeSummarizer      string
+	// Workers is how many jobs the scheduler runs at the same time, set with
+	// WORKERS. Jobs waiting for a worker are queued, on-demand ones first.
+	Workers        int
+	ProfileWorkers int
+	// StoryWorkers above 1 summarize stories of a profile in parallel, the
+	// prompts then lose the summaries of earlier stories as context
+	StoryWorkers         int
+	OpenAIConcurrency    int
+	GeminiConcurrency    int
+	Plans                string
+	HistoryDays          int
+	DefaultPlan          string
+	AllowAnonymous       bool
+	AuthDisabled         bool
+	APIKeys              string
+	JWTSecret            string
+	JWTIssuer            string
+	AdminSubjects        string
+	TLSCert              string
+	TLSKey               string
+	TLSClientCA          string
+	TLSRequireClientCert bool
+	DailyDigests         bool
+	DailyDigestAt        string
+	DailyCheckEvery      time.Duration
+	DailyAttempts        int
+	DailyRetryBackoff    time.Duration
+	DeliveryAttempts     int
+	DeliveryBackoff      time.Duration
+	SMTPHost             string
+	SMTPPort             int
+	SMTPUsername         string
+	SMTPPassword         string
+	SMTPFrom             string
+	TelegramToken        string
+	TelegramAPI          string
+	FeedAddress          string
+	FeedSecret           string
+	FeedBaseURL          string
+	PromptsDir           string
+	RelevanceThreshold   float64
+	OpenAITimeout        time.Duration
+	GeminiTimeout        time.Duration
+	ShotstackTimeout     time.Duration
+	RetryAttempts        int
+	RetryBackoff         time.Duration
+	RetryMaxBackoff      time.Duration
+	BreakerFailures      int
+	BreakerCooldown      time.Duration
+	ImageFallback        string
+	VideoFallback        string
+	MergeFallback        string
+	Renderer             string
+	FFmpegPath           string
+	RenderSoundtrack     string
+	RenderDir            string
+	RenderBaseURL        string
 	Port                 int
 	mu                   sync.Mutex
 }
 
-type queueLength struct {
-	length int
-	mu     sync.Mutex
-}
-
 var Config AppConfig
-var QueueLength queueLength
 
 func init() {
 	// Load environment variables from .env file
@@ -38,27 +99,109 @@ func init() {
 
 	Config = AppConfig{
 		IsBusy:               false,
-		Queue:                []string{},
 		RedisHistoryAddress:  os.Getenv("REDIS_HISTORY_ADDRESS"),
 		RedisHistoryPassword: os.Getenv("REDIS_HISTORY_PASSWORD"),
 		RedisCookiesAddress:  os.Getenv("REDIS_COOKIES_ADDRESS"),
 		RedisCookiesPassword: os.Getenv("REDIS_COOKIES_PASSWORD"),
 		DefaultLogin:         os.Getenv("DEFAULT_LOGIN"),
 		DefaultPassword:      os.Getenv("DEFAULT_PASSWORD"),
+		InstagramAccounts:    os.Getenv("INSTAGRAM_ACCOUNTS"),
+		AccountRotateEvery:   getEnvInt("ACCOUNT_ROTATE_EVERY", 0),
+		SessionRefresh:       getEnvDuration("SESSION_REFRESH", 12*time.Hour),
+		StoryFetcher:         getEnv("STORY_FETCHER", "instagram"),
+		FixturesDir:          getEnv("FIXTURES_DIR", "fixtures"),
+		RecordFixtures:       os.Getenv("RECORD_FIXTURES"),
 		OpenAiKey:            os.Getenv("OPENAI_KEY"),
 		GeminiKey:            os.Getenv("GEMINI_KEY"),
+		ImageSummarizer:      getEnv("IMAGE_SUMMARIZER", "openai"),
+		VideoSummarizer:      getEnv("VIDEO_SUMMARIZER", "gemini"),
+		MergeSummarizer:      getEnv("MERGE_SUMMARIZER", "openai"),
+		Workers:              getEnvInt("WORKERS", 2),
+		ProfileWorkers:       getEnvInt("PROFILE_WORKERS", 4),
+		StoryWorkers:         getEnvInt("STORY_WORKERS", 1),
+		OpenAIConcurrency:    getEnvInt("OPENAI_CONCURRENCY", 8),
+		GeminiConcurrency:    getEnvInt("GEMINI_CONCURRENCY", 4),
+		Plans:                getEnv("PLANS", "free:10"),
+		HistoryDays:          getEnvInt("HISTORY_DAYS", 7),
+		DefaultPlan:          getEnv("DEFAULT_PLAN", "free"),
+		AllowAnonymous:       os.Getenv("ALLOW_ANONYMOUS") == "true",
+		AuthDisabled:         os.Getenv("AUTH_DISABLED") == "true",
+		APIKeys:              os.Getenv("API_KEYS"),
+		JWTSecret:            os.Getenv("JWT_SECRET"),
+		JWTIssuer:            os.Getenv("JWT_ISSUER"),
+		AdminSubjects:        os.Getenv("ADMIN_SUBJECTS"),
+		TLSCert:              os.Getenv("TLS_CERT"),
+		TLSKey:               os.Getenv("TLS_KEY"),
+		TLSClientCA:          os.Getenv("TLS_CLIENT_CA"),
+		TLSRequireClientCert: os.Getenv("TLS_REQUIRE_CLIENT_CERT") == "true",
+		DailyDigests:         os.Getenv("DAILY_DIGESTS") == "true",
+		DailyDigestAt:        getEnv("DAILY_DIGEST_AT", "08:00"),
+		DailyCheckEvery:      getEnvDuration("DAILY_CHECK_EVERY", time.Minute),
+		DailyAttempts:        getEnvInt("DAILY_ATTEMPTS", 4),
+		DailyRetryBackoff:    getEnvDuration("DAILY_RETRY_BACKOFF", 5*time.Minute),
+		DeliveryAttempts:     getEnvInt("DELIVERY_ATTEMPTS", 3),
+		DeliveryBackoff:      getEnvDuration("DELIVERY_BACKOFF", 5*time.Second),
+		SMTPHost:             os.Getenv("SMTP_HOST"),
+		SMTPPort:             getEnvInt("SMTP_PORT", 587),
+		SMTPUsername:         os.Getenv("SMTP_USERNAME"),
+		SMTPPassword:         os.Getenv("SMTP_PASSWORD"),
+		SMTPFrom:             os.Getenv("SMTP_FROM"),
+		TelegramToken:        os.Getenv("TELEGRAM_TOKEN"),
+		TelegramAPI:          getEnv("TELEGRAM_API", "https://api.telegram.org"),
+		FeedAddress:          os.Getenv("FEED_ADDRESS"),
+		FeedSecret:           os.Getenv("FEED_SECRET"),
+		FeedBaseURL:          os.Getenv("FEED_BASE_URL"),
+		PromptsDir:           os.Getenv("PROMPTS_DIR"),
+		RelevanceThreshold:   getEnvFloat("RELEVANCE_THRESHOLD", 0.5),
+		OpenAITimeout:        getEnvDuration("OPENAI_TIMEOUT", time.Minute),
+		GeminiTimeout:        getEnvDuration("GEMINI_TIMEOUT", 3*time.Minute),
+		ShotstackTimeout:     getEnvDuration("SHOTSTACK_TIMEOUT", 30*time.Second),
+		RetryAttempts:        getEnvInt("RETRY_ATTEMPTS", 3),
+		RetryBackoff:         getEnvDuration("RETRY_BACKOFF", time.Second),
+		RetryMaxBackoff:      getEnvDuration("RETRY_MAX_BACKOFF", 30*time.Second),
+		BreakerFailures:      getEnvInt("BREAKER_FAILURES", 5),
+		BreakerCooldown:      getEnvDuration("BREAKER_COOLDOWN", time.Minute),
+		ImageFallback:        os.Getenv("IMAGE_FALLBACK"),
+		VideoFallback:        os.Getenv("VIDEO_FALLBACK"),
+		MergeFallback:        os.Getenv("MERGE_FALLBACK"),
+		Renderer:             getEnv("RENDERER", "shotstack"),
+		FFmpegPath:           getEnv("FFMPEG_PATH", "ffmpeg"),
+		RenderSoundtrack:     os.Getenv("RENDER_SOUNDTRACK"),
+		RenderDir:            getEnv("RENDER_DIR", "renders"),
+		RenderBaseURL:        os.Getenv("RENDER_BASE_URL"),
 		Port:                 5000, // Default port, update as needed
 	}
-	QueueLength = queueLength{
-		length: 0,
-		mu:     sync.Mutex{},
+}
+
+func getEnv(key string, fallback string) string {
+	if value := os.Getenv(key); value != "" {
+		return value
+	}
+	return fallback
+}
+
+func getEnvInt(key string, fallback int) int {
+	value, err := strconv.Atoi(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
+}
+
+func getEnvFloat(key string, fallback float64) float64 {
+	value, err := strconv.ParseFloat(os.Getenv(key), 64)
+	if err != nil {
+		return fallback
 	}
+	return value
 }
 
-func GetQueueLength() int {
-	QueueLength.mu.Lock()
-	defer QueueLength.mu.Unlock()
-	return QueueLength.length
+func getEnvDuration(key string, fallback time.Duration) time.Duration {
+	value, err := time.ParseDuration(os.Getenv(key))
+	if err != nil {
+		return fallback
+	}
+	return value
 }
 
 func SetBusy() {
@@ -78,50 +221,3 @@ func IsBusy() bool {
 	defer Config.mu.Unlock()
 	return Config.IsBusy
 }
-
-func Enqueue(item string, length int) {
-	Config.mu.Lock()
-	defer Config.mu.Unlock()
-	QueueLength.mu.Lock()
-	defer QueueLength.mu.Unlock()
-	QueueLength.length += length
-	Config.Queue = append(Config.Queue, item)
-}
-
-func Dequeue() string {
-	Config.mu.Lock()
-	defer Config.mu.Unlock()
-
-	if len(Config.Queue) == 0 {
-		return ""
-	}
-
-	item := Config.Queue[0]
-	Config.Queue = Config.Queue[1:]
-	return item
-}
-
-func RemoveFromQueue(item string, length int) {
-	Config.mu.Lock()
-	defer Config.mu.Unlock()
-	QueueLength.mu.Lock()
-	defer QueueLength.mu.Unlock()
-	QueueLength.length -= length
-
-	for i, v := range Config.Queue {
-		if v == item {
-			Config.Queue = append(Config.Queue[:i], Config.Queue[i+1:]...)
-		}
-	}
-}
-
-func NextInQueue() string {
-	Config.mu.Lock()
-	defer Config.mu.Unlock()
-
-	if len(Config.Queue) == 0 {
-		return ""
-	}
-
-	return Config.Queue[0]
-}
@@ -0,0 +1,207 @@
+package auth
+
+import (
+	"context"
+	"crypto/subtle"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"os"
+	"strings"
+	"time"
+)
+
+const (
+	APIKey      = "api_key"
+	JWT         = "jwt"
+	Certificate = "certificate"
+)
+
+// Admin services are only open to admin callers
+var adminServices = []string{"/agent.AccountsAdmin/", "/agent.UsersAdmin/"}
+
+// Identity is the authenticated caller of a request. Subject is the user ID
+// of the caller.
+type Identity struct {
+	Subject string
+	Method  string
+	Admin   bool
+}
+
+type identityKey struct{}
+
+func NewContext(ctx context.Context, identity *Identity) context.Context {
+	return context.WithValue(ctx, identityKey{}, identity)
+}
+
+// FromContext returns the caller of a request, ok is false when authentication is disabled
+func FromContext(ctx context.Context) (*Identity, bool) {
+	identity, ok := ctx.Value(identityKey{}).(*Identity)
+	return identity, ok
+}
+
+// Check fails at startup when authentication is enabled without any way to pass it
+func Check() error {
+	if config.Config.AuthDisabled {
+		return nil
+	}
+	if _, err := apiKeys(); err != nil {
+		return err
+	}
+	if config.Config.APIKeys == "" && config.Config.JWTSecret == "" && config.Config.TLSClientCA == "" {
+		return errors.New("authentication is enabled but no API keys, JWT secret or client CA are configured")
+	}
+	return nil
+}
+
+// apiKeys parses config.Config.APIKeys, a list like "key:subject,key2:subject2:admin"
+func apiKeys() (map[string]Identity, error) {
+	keys := make(map[string]Identity)
+	for _, entry := range strings.Split(config.Config.APIKeys, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 || parts[0] == "" || parts[1] == "" {
+			return nil, fmt.Errorf("invalid API key entry, expected key:subject[:admin]")
+		}
+		keys[parts[0]] = Identity{Subject: parts[1], Method: APIKey, Admin: len(parts) == 3 && parts[2] == "admin"}
+	}
+	return keys, nil
+}
+
+// authenticate finds the caller of a request from a verified client certificate,
+// a bearer JWT or an API key in metadata, in that order
+func authenticate(ctx context.Context) (*Identity, error) {
+	if p, ok := peer.FromContext(ctx); ok {
+		if info, ok := p.AuthInfo.(credentials.TLSInfo); ok && len(info.State.VerifiedChains) > 0 {
+			cert := info.State.VerifiedChains[0][0]
+			return &Identity{Subject: cert.Subject.CommonName, Method: Certificate, Admin: isAdminSubject(cert.Subject.CommonName)}, nil
+		}
+	}
+
+	md, _ := metadata.FromIncomingContext(ctx)
+	if values := md.Get("authorization"); len(values) > 0 && config.Config.JWTSecret != "" {
+		token, ok := strings.CutPrefix(values[0], "Bearer ")
+		if !ok {
+			return nil, status.Error(codes.Unauthenticated, "authorization must be a bearer token")
+		}
+		c, err := verifyJWT(token, []byte(config.Config.JWTSecret), config.Config.JWTIssuer, time.Now())
+		if err != nil {
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return &Identity{Subject: c.Subject, Method: JWT, Admin: c.Admin || isAdminSubject(c.Subject)}, nil
+	}
+
+	if values := md.Get("x-api-key"); len(values) > 0 {
+		keys, err := apiKeys()
+		if err != nil {
+			return nil, status.Error(codes.Internal, "invalid API keys configuration")
+		}
+		for key, identity := range keys {
+			if subtle.ConstantTimeCompare([]byte(key), []byte(values[0])) == 1 {
+				identity.Admin = identity.Admin || isAdminSubject(identity.Subject)
+				return &identity, nil
+			}
+		}
+		return nil, status.Error(codes.Unauthenticated, "invalid API key")
+	}
+	return nil, status.Error(codes.Unauthenticated, "missing credentials")
+}
+
+func isAdminSubject(subject string) bool {
+	for _, admin := range strings.Split(config.Config.AdminSubjects, ",") {
+		if admin = strings.TrimSpace(admin); admin != "" && admin == subject {
+			return true
+		}
+	}
+	return false
+}
+
+func authorize(ctx context.Context, method string) (context.Context, error) {
+	if config.Config.AuthDisabled {
+		return ctx, nil
+	}
+	identity, err := authenticate(ctx)
+	if err != nil {
+		return nil, err
+	}
+	for _, service := range adminServices {
+		if strings.HasPrefix(method, service) && !identity.Admin {
+			return nil, status.Errorf(codes.PermissionDenied, "%s requires an admin", method)
+		}
+	}
+	return NewContext(ctx, identity), nil
+}
+
+func UnaryInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	ctx, err := authorize(ctx, info.FullMethod)
+	if err != nil {
+		return nil, err
+	}
+	return handler(ctx, req)
+}
+
+func StreamInterceptor(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+	ctx, err := authorize(ss.Context(), info.FullMethod)
+	if err != nil {
+		return err
+	}
+	return handler(srv, &serverStream{ServerStream: ss, ctx: ctx})
+}
+
+// serverStream replaces the context of a stream with one carrying the identity
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+// ServerOptions returns interceptors and, when a certificate is configured,
+// TLS credentials. A client CA turns on mTLS.
+func ServerOptions() ([]grpc.ServerOption, error) {
+	options := []grpc.ServerOption{
+		grpc.UnaryInterceptor(UnaryInterceptor),
+		grpc.StreamInterceptor(StreamInterceptor),
+	}
+	if config.Config.TLSCert == "" {
+		if config.Config.TLSClientCA != "" {
+			return nil, errors.New("client CA is configured without a server certificate")
+		}
+		return options, nil
+	}
+
+	cert, err := tls.LoadX509KeyPair(config.Config.TLSCert, config.Config.TLSKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load TLS certificate: %v", err)
+	}
+	tlsConfig := &tls.Config{Certificates: []tls.Certificate{cert}, MinVersion: tls.VersionTLS12}
+	if config.Config.TLSClientCA != "" {
+		ca, err := os.ReadFile(config.Config.TLSClientCA)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read client CA: %v", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(ca) {
+			return nil, errors.New("client CA contains no certificates")
+		}
+		tlsConfig.ClientCAs = pool
+		tlsConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if config.Config.TLSRequireClientCert {
+			tlsConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+	}
+	return append(options, grpc.Creds(credentials.NewTLS(tlsConfig))), nil
+}
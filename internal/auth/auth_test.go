@@ -0,0 +1,149 @@
+package auth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/json"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/config"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"testing"
+	"time"
+)
+
+var now = time.Unix(1700000000, 0)
+
+// token makes a JWT of claims with alg in its header, always signed with HS256
+func token(alg string, claims map[string]interface{}, secret string) string {
+	segment := func(v interface{}) string {
+		data, _ := json.Marshal(v)
+		return base64.RawURLEncoding.EncodeToString(data)
+	}
+	unsigned := segment(map[string]string{"alg": alg, "typ": "JWT"}) + "." + segment(claims)
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(unsigned))
+	return unsigned + "." + base64.RawURLEncoding.EncodeToString(mac.Sum(nil))
+}
+
+func TestVerifyJWT(t *testing.T) {
+	valid := map[string]interface{}{"sub": "alice", "iss": "issuer", "exp": now.Unix() + 60}
+	with := func(key string, value interface{}) map[string]interface{} {
+		claims := map[string]interface{}{}
+		for k, v := range valid {
+			claims[k] = v
+		}
+		if value == nil {
+			delete(claims, key)
+		} else {
+			claims[key] = value
+		}
+		return claims
+	}
+	tests := []struct {
+		name  string
+		token string
+		ok    bool
+	}{
+		{"valid", token("HS256", valid, "secret"), true},
+		{"past nbf", token("HS256", with("nbf", now.Unix()-60), "secret"), true},
+		{"bad signature", token("HS256", valid, "other"), false},
+		{"alg none", token("none", valid, "secret"), false},
+		{"alg HS512", token("HS512", valid, "secret"), false},
+		{"missing exp", token("HS256", with("exp", nil), "secret"), false},
+		{"expired", token("HS256", with("exp", now.Unix()), "secret"), false},
+		{"future nbf", token("HS256", with("nbf", now.Unix()+60), "secret"), false},
+		{"issuer mismatch", token("HS256", with("iss", "other"), "secret"), false},
+		{"missing issuer", token("HS256", with("iss", nil), "secret"), false},
+		{"no subject", token("HS256", with("sub", nil), "secret"), false},
+		{"malformed", "not.a-token", false},
+	}
+	for _, tt := range tests {
+		c, err := verifyJWT(tt.token, []byte("secret"), "issuer", now)
+		if tt.ok && (err != nil || c.Subject != "alice") {
+			t.Errorf("%s: verifyJWT = %+v, %v, want alice", tt.name, c, err)
+		}
+		if !tt.ok && !errors.Is(err, ErrInvalidToken) {
+			t.Errorf("%s: verifyJWT = %+v, %v, want ErrInvalidToken", tt.name, c, err)
+		}
+	}
+}
+
+func TestAuthenticate(t *testing.T) {
+	defer setConfig(&config.Config.APIKeys, "key1:alice,key2:root:admin")()
+	defer setConfig(&config.Config.JWTSecret, "secret")()
+	defer setConfig(&config.Config.JWTIssuer, "")()
+	defer setConfig(&config.Config.AdminSubjects, "carol")()
+
+	exp := time.Now().Add(time.Minute).Unix()
+	tests := []struct {
+		name     string
+		metadata []string
+		subject  string
+		admin    bool
+		code     codes.Code
+	}{
+		{"api key", []string{"x-api-key", "key1"}, "alice", false, codes.OK},
+		{"admin api key", []string{"x-api-key", "key2"}, "root", true, codes.OK},
+		{"unknown api key", []string{"x-api-key", "key3"}, "", false, codes.Unauthenticated},
+		{"jwt", []string{"authorization", "Bearer " + token("HS256", map[string]interface{}{"sub": "bob", "exp": exp}, "secret")}, "bob", false, codes.OK},
+		{"admin subject", []string{"authorization", "Bearer " + token("HS256", map[string]interface{}{"sub": "carol", "exp": exp}, "secret")}, "carol", true, codes.OK},
+		{"bad jwt", []string{"authorization", "Bearer " + token("HS256", map[string]interface{}{"sub": "bob", "exp": exp}, "other")}, "", false, codes.Unauthenticated},
+		{"not bearer", []string{"authorization", "Basic Ym9iOmJvYg=="}, "", false, codes.Unauthenticated},
+		{"missing credentials", nil, "", false, codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs(tt.metadata...))
+		identity, err := authenticate(ctx)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: authenticate = %v, want %s", tt.name, err, tt.code)
+			continue
+		}
+		if err == nil && (identity.Subject != tt.subject || identity.Admin != tt.admin) {
+			t.Errorf("%s: identity = %+v, want %s admin %v", tt.name, identity, tt.subject, tt.admin)
+		}
+	}
+}
+
+func TestAuthorize(t *testing.T) {
+	defer setConfig(&config.Config.AuthDisabled, false)()
+	defer setConfig(&config.Config.APIKeys, "key1:alice,key2:root:admin")()
+
+	tests := []struct {
+		key    string
+		method string
+		code   codes.Code
+	}{
+		{"key1", "/agent.StoriesSummarizer/SummarizeStories", codes.OK},
+		{"key1", "/agent.AccountsAdmin/ListAccounts", codes.PermissionDenied},
+		{"key1", "/agent.AccountsAdmin/StartLogin", codes.PermissionDenied},
+		{"key1", "/agent.UsersAdmin/PutUser", codes.PermissionDenied},
+		{"key1", "/agent.UsersAdmin/GetUsage", codes.PermissionDenied},
+		{"key2", "/agent.AccountsAdmin/ListAccounts", codes.OK},
+		{"key2", "/agent.UsersAdmin/PutUser", codes.OK},
+		{"key3", "/agent.StoriesSummarizer/GetJob", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-api-key", tt.key))
+		ctx, err := authorize(ctx, tt.method)
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s calling %s: authorize = %v, want %s", tt.key, tt.method, err, tt.code)
+			continue
+		}
+		if err == nil {
+			if _, ok := FromContext(ctx); !ok {
+				t.Errorf("%s calling %s: no identity in context", tt.key, tt.method)
+			}
+		}
+	}
+}
+
+// setConfig sets a config value for a test and returns a func restoring it
+func setConfig[T any](field *T, value T) func() {
+	old := *field
+	*field = value
+	return func() { *field = old }
+}
@@ -0,0 +1,109 @@
+package budget
+
+import (
+	"errors"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"sync"
+)
+
+var ErrExhausted = errors.New("usage limit reached")
+
+// StoryCost is what summarizing one story with a provider costs, cached summaries are free
+const StoryCost float32 = 1
+
+// Ledger is where reserved units are charged outside of the job, for example
+// the quota of the user the job runs for
+type Ledger interface {
+	Reserve(units float32) error
+	Refund(units float32) error
+}
+
+// Budget tracks the units a job may spend. Units are reserved before a provider
+// is called, so concurrent calls can never spend more than the job was given.
+type Budget struct {
+	mu       sync.Mutex
+	left     float32
+	used     float32
+	reserved float32
+	ledger   Ledger
+}
+
+// New returns a budget that already used used units out of left. Reservations
+// are charged to ledger too when it is not nil.
+func New(used float32, left float32, ledger Ledger) *Budget {
+	return &Budget{used: used, left: left, ledger: ledger}
+}
+
+// Reserve holds units for a provider call, it fails with ErrExhausted when
+// they would go over the limit together with everything used and reserved,
+// or with the error of the ledger
+func (b *Budget) Reserve(units float32) (*Reservation, error) {
+	b.mu.Lock()
+	if b.used+b.reserved+units > b.left {
+		b.mu.Unlock()
+		return nil, ErrExhausted
+	}
+	b.reserved += units
+	b.mu.Unlock()
+
+	if b.ledger != nil {
+		if err := b.ledger.Reserve(units); err != nil {
+			b.mu.Lock()
+			b.reserved -= units
+			b.mu.Unlock()
+			return nil, err
+		}
+	}
+	return &Reservation{budget: b, units: units}, nil
+}
+
+// Used returns the units spent so far, reservations are not counted until committed
+func (b *Budget) Used() float32 {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	return b.used
+}
+
+// Reservation is a hold on units of a budget, it is settled once by Commit or Refund
+type Reservation struct {
+	budget  *Budget
+	units   float32
+	settled bool
+}
+
+func (r *Reservation) Units() float32 {
+	return r.units
+}
+
+// Commit spends the reserved units and returns the usage of the budget after it
+func (r *Reservation) Commit() float32 {
+	b := r.budget
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	if !r.settled {
+		r.settled = true
+		b.reserved -= r.units
+		b.used += r.units
+	}
+	return b.used
+}
+
+// Refund gives the reserved units back, used when the provider call failed
+func (r *Reservation) Refund() {
+	b := r.budget
+	b.mu.Lock()
+	if r.settled {
+		b.mu.Unlock()
+		return
+	}
+	r.settled = true
+	b.reserved -= r.units
+	b.mu.Unlock()
+
+	if b.ledger != nil {
+		if err := b.ledger.Refund(r.units); err != nil {
+			logger.Error("Error refunding units to ledger", zap.Float32("units", r.units), zap.Error(err))
+		}
+	}
+}
@@ -0,0 +1,135 @@
+package cache
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"time"
+)
+
+// Summaries of stories are cached while the story is alive
+const ttl = 24 * time.Hour
+
+const (
+	hitsCounter   = "summary_cache_hits"
+	missesCounter = "summary_cache_misses"
+)
+
+// Key builds the cache key of a story media from its PK and version. Medias
+// without a PK are keyed by a hash of their content, never by the signed URL.
+func Key(ctx context.Context, media inst.Media) (string, error) {
+	if media.ID != "" {
+		return fmt.Sprintf("summary:%s:%s", media.ID, media.Version), nil
+	}
+	hash, err := contentHash(ctx, media.URL)
+	if err != nil {
+		return "", err
+	}
+	return "summary:sha256:" + hash, nil
+}
+
+func contentHash(ctx context.Context, url string) (string, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download media: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download media, status code: %d", resp.StatusCode)
+	}
+	hash := sha256.New()
+	if _, err = io.Copy(hash, resp.Body); err != nil {
+		return "", fmt.Errorf("failed to read media: %w", err)
+	}
+	return hex.EncodeToString(hash.Sum(nil)), nil
+}
+
+// entry is a cached summary. Entries cached before relevance was asked for
+// have no interesting and relevance.
+type entry struct {
+	Value       string   `json:"value"`
+	AddIt       bool     `json:"addIt"`
+	ClipLength  int      `json:"clipLength,omitempty"`
+	Interesting *bool    `json:"interesting,omitempty"`
+	Relevance   *float64 `json:"relevance,omitempty"`
+	Prompt      string   `json:"prompt,omitempty"`
+}
+
+func (e entry) story() reply.Story {
+	story := reply.Story{Description: e.Value, AddIt: e.AddIt, ClipLength: e.ClipLength, Interesting: !reply.NothingInteresting(e.Value), Relevance: 1}
+	if e.Interesting != nil {
+		story.Interesting = *e.Interesting
+	}
+	if e.Relevance != nil {
+		story.Relevance = *e.Relevance
+	}
+	return story
+}
+
+// Get returns the cached summary of key, ok is false on a miss. Hits and misses are counted.
+func Get(ctx context.Context, key string) (reply.Story, bool) {
+	value, err := redis.GetSummary(ctx, key)
+	if err != nil {
+		if !errors.Is(err, redis.ErrNotExist) {
+			logger.Error("Error reading summary cache", zap.String("key", key), zap.Error(err))
+		}
+		count(ctx, missesCounter)
+		return reply.Story{}, false
+	}
+	count(ctx, hitsCounter)
+	var cached entry
+	if err = json.Unmarshal([]byte(value), &cached); err != nil {
+		// Oldest entries are the bare summary
+		cached = entry{Value: value}
+	}
+	return cached.story(), true
+}
+
+// Store caches a summary with the version of the prompt it was made with
+func Store(ctx context.Context, key string, story reply.Story, prompt string) error {
+	value, err := json.Marshal(entry{
+		Value:       story.Description,
+		AddIt:       story.AddIt,
+		ClipLength:  story.ClipLength,
+		Interesting: &story.Interesting,
+		Relevance:   &story.Relevance,
+		Prompt:      prompt,
+	})
+	if err != nil {
+		return fmt.Errorf("failed to encode summary: %w", err)
+	}
+	return redis.StoreSummarizes(ctx, key, nil, string(value), ttl)
+}
+
+// Stats returns how many lookups hit and missed the cache since the counters were created
+func Stats(ctx context.Context) (int64, int64, error) {
+	hits, err := redis.GetCounter(ctx, hitsCounter)
+	if err != nil {
+		return 0, 0, err
+	}
+	misses, err := redis.GetCounter(ctx, missesCounter)
+	if err != nil {
+		return 0, 0, err
+	}
+	return hits, misses, nil
+}
+
+func count(ctx context.Context, name string) {
+	if err := redis.IncrCounter(context.WithoutCancel(ctx), name); err != nil {
+		logger.Error("Error counting summary cache lookup", zap.String("counter", name), zap.Error(err))
+	}
+}
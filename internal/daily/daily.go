@@ -0,0 +1,210 @@
+package daily
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/server"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+// Runner starts the daily digest of every daily user once a day at their
+// local digest time. Failed digests are retried with exponential backoff,
+// users whose stories did not change since their last digest are skipped.
+type Runner struct {
+	Server *server.Server
+
+	mu      sync.Mutex
+	running map[string]bool
+}
+
+// Run checks for due digests every config.Config.DailyCheckEvery until ctx is done
+func (r *Runner) Run(ctx context.Context) {
+	r.running = make(map[string]bool)
+	ticker := time.NewTicker(config.Config.DailyCheckEvery)
+	defer ticker.Stop()
+	for {
+		r.check(ctx, time.Now())
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+func (r *Runner) check(ctx context.Context, now time.Time) {
+	ids, err := users.IDs(ctx)
+	if err != nil {
+		logger.Error("Error loading users for daily digests", zap.Error(err))
+		return
+	}
+	for _, id := range ids {
+		user, err := users.Get(ctx, id)
+		if err != nil {
+			logger.Error("Error loading user for daily digest", zap.String("user", id), zap.Error(err))
+			continue
+		}
+		if !user.Daily || len(user.Usernames) == 0 {
+			continue
+		}
+		date, ok := due(user, now)
+		if !ok {
+			continue
+		}
+		state, err := jobs.GetDaily(ctx, id)
+		if errors.Is(err, redis.ErrNotExist) {
+			state = &jobs.Daily{UserID: id}
+		} else if err != nil {
+			logger.Error("Error loading daily digest", zap.String("user", id), zap.Error(err))
+			continue
+		}
+		if state.Date == date && !r.retry(id, state, now) {
+			continue
+		}
+		if state.Date == date && state.JobID != "" && resumed(ctx, state) {
+			continue
+		}
+		if state.Date != date {
+			state.Date = date
+			state.Attempts = 0
+			state.JobID = ""
+			state.Error = ""
+		}
+		if !r.start(id) {
+			continue
+		}
+		go func(user *users.User, state *jobs.Daily) {
+			defer r.finish(user.ID)
+			r.digest(ctx, user, state)
+		}(user, state)
+	}
+}
+
+// retry reports whether the digest of today is tried again. A digest left running
+// by a process that stopped is tried again unless its job was resumed.
+func (r *Runner) retry(id string, state *jobs.Daily, now time.Time) bool {
+	switch state.Status {
+	case jobs.DailyRetrying:
+		return !now.Before(state.NextAttempt)
+	case jobs.DailyRunning:
+		r.mu.Lock()
+		defer r.mu.Unlock()
+		return !r.running[id]
+	}
+	return false
+}
+
+func (r *Runner) start(id string) bool {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if r.running[id] {
+		return false
+	}
+	r.running[id] = true
+	return true
+}
+
+func (r *Runner) finish(id string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	delete(r.running, id)
+}
+
+// resumed reports whether the job of an interrupted digest was resumed by the
+// server, a finished one completes the digest
+func resumed(ctx context.Context, state *jobs.Daily) bool {
+	job, err := jobs.Get(ctx, state.JobID)
+	if err != nil {
+		return false
+	}
+	switch job.Status {
+	case jobs.Queued, jobs.Running:
+		return true
+	case jobs.Done:
+		finished(state, job)
+		return true
+	}
+	return false
+}
+
+// finished records the outcome of the done job of a digest. The fingerprint
+// is kept when the job could not fetch any profile.
+func finished(state *jobs.Daily, job *jobs.Job) {
+	state.Status = jobs.DailyDone
+	if job.Unchanged {
+		logger.Info("Stories did not change, daily digest skipped", zap.String("user", state.UserID))
+		state.Status = jobs.DailyUnchanged
+	}
+	state.Error = ""
+	if job.Fingerprint != "" {
+		state.Fingerprint = job.Fingerprint
+	}
+	save(state)
+}
+
+// due returns the local date of user when their digest time of that date has passed
+func due(user *users.User, now time.Time) (string, bool) {
+	location, err := user.Location()
+	if err != nil {
+		return "", false
+	}
+	hour, minute, err := user.DigestTime()
+	if err != nil {
+		return "", false
+	}
+	local := now.In(location)
+	at := time.Date(local.Year(), local.Month(), local.Day(), hour, minute, 0, 0, location)
+	return local.Format("2006-01-02"), !local.Before(at)
+}
+
+// digest makes one attempt at the digest of user and records its outcome in state
+func (r *Runner) digest(ctx context.Context, user *users.User, state *jobs.Daily) {
+	state.Status = jobs.DailyRunning
+	state.Attempts++
+	save(state)
+
+	job, err := server.DailyJob(ctx, user)
+	if err == nil {
+		job.Previous = state.Fingerprint
+		// The job ID is saved first, a job resumed after a restart is not started twice
+		state.JobID = job.ID
+		save(state)
+		err = r.Server.RunJob(ctx, job)
+	}
+	if err == nil {
+		finished(state, job)
+		return
+	}
+
+	logger.Error("Daily digest failed", zap.String("user", user.ID), zap.Int("attempt", state.Attempts), zap.Error(err))
+	state.Error = err.Error()
+	if errors.Is(err, redis.ErrQuotaExceeded) || state.Attempts >= config.Config.DailyAttempts {
+		state.Status = jobs.DailyFailed
+	} else {
+		state.Status = jobs.DailyRetrying
+		state.NextAttempt = time.Now().Add(backoff(state.Attempts))
+	}
+	save(state)
+}
+
+// backoff doubles the retry delay with every failed attempt
+func backoff(attempts int) time.Duration {
+	delay := config.Config.DailyRetryBackoff
+	for i := 1; i < attempts; i++ {
+		delay *= 2
+	}
+	return delay
+}
+
+func save(state *jobs.Daily) {
+	if err := jobs.SaveDaily(context.Background(), state); err != nil {
+		logger.Error("Error saving daily digest", zap.String("user", state.UserID), zap.Error(err))
+	}
+}
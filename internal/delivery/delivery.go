@@ -0,0 +1,117 @@
+package delivery
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"time"
+)
+
+var ErrNotConfigured = errors.New("delivery channel is not configured")
+
+// Digest is a finished digest as it is delivered to a user. Result is the JSON
+// result of the job, Text and HTML the same digest for people to read.
+type Digest struct {
+	UserID      string    `json:"userId"`
+	JobID       string    `json:"jobId"`
+	Result      string    `json:"result"`
+	LinkToVideo string    `json:"linkToVideo,omitempty"`
+	Text        string    `json:"text"`
+	HTML        string    `json:"html"`
+	CreatedAt   time.Time `json:"createdAt"`
+}
+
+// Status is the outcome of delivering a digest to one channel
+type Status struct {
+	Channel   string    `json:"channel"`
+	Target    string    `json:"target"`
+	Delivered bool      `json:"delivered"`
+	Attempts  int       `json:"attempts"`
+	Error     string    `json:"error"`
+	At        time.Time `json:"at"`
+}
+
+// Sender delivers a digest to a single channel
+type Sender interface {
+	Send(ctx context.Context, channel users.Channel, digest Digest) error
+}
+
+func senderOf(channel string) (Sender, error) {
+	switch channel {
+	case users.Webhook:
+		return Webhook{}, nil
+	case users.Email:
+		return SMTP{}, nil
+	case users.Telegram:
+		return Telegram{}, nil
+	}
+	return nil, fmt.Errorf("unknown delivery channel %q", channel)
+}
+
+// Deliver sends digest to every channel of user. Each channel is retried with
+// exponential backoff, the returned statuses are in the order of the channels.
+func Deliver(ctx context.Context, user *users.User, digest Digest) []Status {
+	statuses := make([]Status, 0, len(user.Channels))
+	for _, channel := range user.Channels {
+		status := deliver(ctx, channel, digest)
+		if !status.Delivered {
+			logger.Error("Failed to deliver digest", zap.String("user", user.ID), zap.String("channel", channel.Type), zap.String("error", status.Error))
+		}
+		statuses = append(statuses, status)
+	}
+	return statuses
+}
+
+func deliver(ctx context.Context, channel users.Channel, digest Digest) Status {
+	status := Status{Channel: channel.Type, Target: channel.Target}
+	sender, err := senderOf(channel.Type)
+	if err != nil {
+		status.Error = err.Error()
+		status.At = time.Now()
+		return status
+	}
+
+	delay := config.Config.DeliveryBackoff
+	for {
+		status.Attempts++
+		err = sender.Send(ctx, channel, digest)
+		if err == nil || errors.Is(err, ErrNotConfigured) || status.Attempts >= config.Config.DeliveryAttempts {
+			break
+		}
+		if err = sleep(ctx, delay); err != nil {
+			break
+		}
+		delay *= 2
+	}
+	status.At = time.Now()
+	status.Delivered = err == nil
+	if err != nil {
+		status.Error = err.Error()
+	}
+	return status
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// text is the plain text body of a digest for email and Telegram
+func text(digest Digest) string {
+	if digest.Text != "" {
+		return digest.Text
+	}
+	body := digest.Result
+	if digest.LinkToVideo != "" {
+		body += "\n\nRecap video: " + digest.LinkToVideo
+	}
+	return body
+}
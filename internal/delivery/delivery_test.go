@@ -0,0 +1,266 @@
+package delivery
+
+import (
+	"bufio"
+	"context"
+	"encoding/json"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"io"
+	"net"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+var digest = Digest{
+	UserID:    "user",
+	JobID:     "job",
+	Result:    `[{"Author":"alice","Summarize":"Went hiking"}]`,
+	Text:      "# Digest\n\nWent hiking",
+	CreatedAt: time.Date(2024, 5, 1, 8, 0, 0, 0, time.UTC),
+}
+
+func TestWebhookSignature(t *testing.T) {
+	var received Digest
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		body, _ := io.ReadAll(r.Body)
+		timestamp := r.Header.Get("X-Timestamp")
+		if _, err := strconv.ParseInt(timestamp, 10, 64); err != nil {
+			t.Errorf("invalid X-Timestamp %q", timestamp)
+		}
+		if got, want := r.Header.Get("X-Signature"), "sha256="+Sign("secret", timestamp, body); got != want {
+			t.Errorf("X-Signature = %q, want %q", got, want)
+		}
+		if err := json.Unmarshal(body, &received); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+	}))
+	defer server.Close()
+
+	err := Webhook{}.Send(context.Background(), users.Channel{Type: users.Webhook, Target: server.URL, Secret: "secret"}, digest)
+	if err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if received.JobID != digest.JobID || received.Result != digest.Result {
+		t.Errorf("received %+v, want %+v", received, digest)
+	}
+}
+
+func TestSign(t *testing.T) {
+	// echo -n '1700000000.{}' | openssl dgst -sha256 -hmac secret
+	const want = "b8569b78799ff9e3cbff0fc2d63a33a2b57f3282abd07c37ae5e8e7d79a5f163"
+	if got := Sign("secret", "1700000000", []byte("{}")); got != want {
+		t.Errorf("Sign = %q, want %q", got, want)
+	}
+}
+
+func TestDeliverRetriesServerErrors(t *testing.T) {
+	defer setConfig(&config.Config.DeliveryAttempts, 3)()
+	defer setConfig(&config.Config.DeliveryBackoff, time.Millisecond)()
+
+	var calls int32
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if atomic.AddInt32(&calls, 1) < 3 {
+			w.WriteHeader(http.StatusBadGateway)
+		}
+	}))
+	defer server.Close()
+
+	status := deliver(context.Background(), users.Channel{Type: users.Webhook, Target: server.URL}, digest)
+	if !status.Delivered || status.Attempts != 3 || status.Error != "" {
+		t.Errorf("status = %+v, want delivered on the third attempt", status)
+	}
+}
+
+func TestDeliverGivesUp(t *testing.T) {
+	defer setConfig(&config.Config.DeliveryAttempts, 2)()
+	defer setConfig(&config.Config.DeliveryBackoff, time.Millisecond)()
+
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusInternalServerError)
+	}))
+	defer server.Close()
+
+	status := deliver(context.Background(), users.Channel{Type: users.Webhook, Target: server.URL}, digest)
+	if status.Delivered || status.Attempts != 2 || !strings.Contains(status.Error, "500") {
+		t.Errorf("status = %+v, want failed after 2 attempts", status)
+	}
+}
+
+func TestTelegram(t *testing.T) {
+	var message map[string]interface{}
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/bot123:token/sendMessage" {
+			t.Errorf("path = %q", r.URL.Path)
+		}
+		if err := json.NewDecoder(r.Body).Decode(&message); err != nil {
+			t.Errorf("invalid body: %v", err)
+		}
+		io.WriteString(w, `{"ok":true}`)
+	}))
+	defer server.Close()
+	defer setConfig(&config.Config.TelegramAPI, server.URL)()
+	defer setConfig(&config.Config.TelegramToken, "123:token")()
+
+	if err := (Telegram{}).Send(context.Background(), users.Channel{Type: users.Telegram, Target: "42"}, digest); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	if message["chat_id"] != "42" || message["text"] != digest.Text {
+		t.Errorf("message = %v", message)
+	}
+}
+
+func TestTelegramRefused(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.WriteHeader(http.StatusBadRequest)
+		io.WriteString(w, `{"ok":false,"description":"Bad Request: chat not found"}`)
+	}))
+	defer server.Close()
+	defer setConfig(&config.Config.TelegramAPI, server.URL)()
+	defer setConfig(&config.Config.TelegramToken, "123:token")()
+
+	err := (Telegram{}).Send(context.Background(), users.Channel{Type: users.Telegram, Target: "42"}, digest)
+	if err == nil || !strings.Contains(err.Error(), "chat not found") {
+		t.Errorf("Send = %v, want the description of Telegram", err)
+	}
+}
+
+func TestTelegramErrorHidesToken(t *testing.T) {
+	server := httptest.NewServer(http.NotFoundHandler())
+	server.Close()
+	defer setConfig(&config.Config.TelegramAPI, server.URL)()
+	defer setConfig(&config.Config.TelegramToken, "123:token")()
+
+	err := (Telegram{}).Send(context.Background(), users.Channel{Type: users.Telegram, Target: "42"}, digest)
+	if err == nil {
+		t.Fatal("Send succeeded against a closed server")
+	}
+	if strings.Contains(err.Error(), "123:token") {
+		t.Errorf("error contains the token: %v", err)
+	}
+	var netErr net.Error
+	if !errors.As(err, &netErr) {
+		t.Errorf("error %v does not wrap the cause", err)
+	}
+}
+
+func TestTelegramNotConfigured(t *testing.T) {
+	defer setConfig(&config.Config.TelegramToken, "")()
+
+	err := (Telegram{}).Send(context.Background(), users.Channel{Type: users.Telegram, Target: "42"}, digest)
+	if !errors.Is(err, ErrNotConfigured) {
+		t.Errorf("Send = %v, want ErrNotConfigured", err)
+	}
+}
+
+func TestSMTP(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		t.Fatal(err)
+	}
+	defer listener.Close()
+	mails := make(chan smtpMail, 1)
+	go serveSMTP(listener, mails)
+
+	host, port, _ := net.SplitHostPort(listener.Addr().String())
+	portNumber, _ := strconv.Atoi(port)
+	defer setConfig(&config.Config.SMTPHost, host)()
+	defer setConfig(&config.Config.SMTPPort, portNumber)()
+	defer setConfig(&config.Config.SMTPUsername, "")()
+	defer setConfig(&config.Config.SMTPFrom, "digest@example.com")()
+
+	withHTML := digest
+	withHTML.HTML = "<p>Went hiking</p>"
+	if err = (SMTP{}).Send(context.Background(), users.Channel{Type: users.Email, Target: "alice@example.com"}, withHTML); err != nil {
+		t.Fatalf("Send: %v", err)
+	}
+	mail := <-mails
+	if mail.from != "<digest@example.com>" || mail.to != "<alice@example.com>" {
+		t.Errorf("envelope = %s -> %s", mail.from, mail.to)
+	}
+	for _, want := range []string{"To: alice@example.com", "Content-Type: text/html", "<p>Went hiking</p>"} {
+		if !strings.Contains(mail.data, want) {
+			t.Errorf("message misses %q:\n%s", want, mail.data)
+		}
+	}
+}
+
+func TestSMTPRejectsHeaderInjection(t *testing.T) {
+	defer setConfig(&config.Config.SMTPHost, "127.0.0.1")()
+
+	target := "alice@example.com\r\nBcc: mallory@example.com"
+	if err := (SMTP{}).Send(context.Background(), users.Channel{Type: users.Email, Target: target}, digest); err == nil {
+		t.Error("Send to an address with a header succeeded")
+	}
+}
+
+type smtpMail struct {
+	from string
+	to   string
+	data string
+}
+
+// serveSMTP accepts a single mail on listener without authentication
+func serveSMTP(listener net.Listener, mails chan<- smtpMail) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+	r := bufio.NewReader(conn)
+	reply := func(line string) { io.WriteString(conn, line+"\r\n") }
+
+	var mail smtpMail
+	reply("220 localhost ESMTP")
+	for {
+		line, err := r.ReadString('\n')
+		if err != nil {
+			return
+		}
+		command := strings.TrimSpace(line)
+		switch verb := strings.ToUpper(strings.SplitN(command, " ", 2)[0]); verb {
+		case "EHLO", "HELO":
+			reply("250 localhost")
+		case "MAIL":
+			mail.from = strings.TrimPrefix(command, "MAIL FROM:")
+			reply("250 OK")
+		case "RCPT":
+			mail.to = strings.TrimPrefix(command, "RCPT TO:")
+			reply("250 OK")
+		case "DATA":
+			reply("354 End data with <CR><LF>.<CR><LF>")
+			var data strings.Builder
+			for {
+				line, err := r.ReadString('\n')
+				if err != nil {
+					return
+				}
+				if line == ".\r\n" {
+					break
+				}
+				data.WriteString(line)
+			}
+			mail.data = data.String()
+			reply("250 OK")
+		case "QUIT":
+			reply("221 Bye")
+			mails <- mail
+			return
+		default:
+			reply("502 Command not implemented")
+		}
+	}
+}
+
+// setConfig sets a config value for a test and returns a func restoring it
+func setConfig[T any](field *T, value T) func() {
+	old := *field
+	*field = value
+	return func() { *field = old }
+}
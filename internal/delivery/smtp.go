@@ -0,0 +1,67 @@
+package delivery
+
+import (
+	"context"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"net"
+	"net/smtp"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// SMTP emails the digest to the address of the channel through the configured server
+type SMTP struct{}
+
+func (SMTP) Send(ctx context.Context, channel users.Channel, digest Digest) error {
+	host := config.Config.SMTPHost
+	if host == "" {
+		return fmt.Errorf("smtp: %w", ErrNotConfigured)
+	}
+	// Targets are validated when users are saved, older ones may not be
+	if strings.ContainsAny(channel.Target, "\r\n") {
+		return fmt.Errorf("invalid email address %q", channel.Target)
+	}
+	addr := net.JoinHostPort(host, strconv.Itoa(config.Config.SMTPPort))
+
+	var auth smtp.Auth
+	if config.Config.SMTPUsername != "" {
+		auth = smtp.PlainAuth("", config.Config.SMTPUsername, config.Config.SMTPPassword, host)
+	}
+
+	// net/smtp has no context support, the send is abandoned when ctx is done
+	done := make(chan error, 1)
+	go func() {
+		done <- smtp.SendMail(addr, auth, config.Config.SMTPFrom, []string{channel.Target}, message(channel.Target, digest))
+	}()
+	select {
+	case err := <-done:
+		if err != nil {
+			return fmt.Errorf("failed to send email: %v", err)
+		}
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func message(to string, digest Digest) []byte {
+	var b strings.Builder
+	b.WriteString("From: " + config.Config.SMTPFrom + "\r\n")
+	b.WriteString("To: " + to + "\r\n")
+	b.WriteString("Subject: Your stories digest\r\n")
+	b.WriteString("Date: " + digest.CreatedAt.Format(time.RFC1123Z) + "\r\n")
+	b.WriteString("MIME-Version: 1.0\r\n")
+	if digest.HTML != "" {
+		b.WriteString("Content-Type: text/html; charset=UTF-8\r\n")
+		b.WriteString("\r\n")
+		b.WriteString(strings.ReplaceAll(digest.HTML, "\n", "\r\n"))
+		return []byte(b.String())
+	}
+	b.WriteString("Content-Type: text/plain; charset=UTF-8\r\n")
+	b.WriteString("\r\n")
+	b.WriteString(strings.ReplaceAll(text(digest), "\n", "\r\n"))
+	return []byte(b.String())
+}
@@ -0,0 +1,72 @@
+package delivery
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// Telegram sends the digest with the Bot API to the chat ID of the channel
+type Telegram struct{}
+
+// Telegram messages are limited to 4096 characters
+const telegramLimit = 4096
+
+func (Telegram) Send(ctx context.Context, channel users.Channel, digest Digest) error {
+	token := config.Config.TelegramToken
+	if token == "" {
+		return fmt.Errorf("telegram: %w", ErrNotConfigured)
+	}
+	message := []rune(text(digest))
+	if len(message) > telegramLimit {
+		message = message[:telegramLimit]
+	}
+	body, err := json.Marshal(map[string]interface{}{"chat_id": channel.Target, "text": string(message)})
+	if err != nil {
+		return fmt.Errorf("failed to marshal telegram message: %v", err)
+	}
+
+	url := fmt.Sprintf("%s/bot%s/sendMessage", config.Config.TelegramAPI, token)
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, url, bytes.NewReader(body))
+	if err != nil {
+		return fmt.Errorf("failed to create telegram request: %v", err)
+	}
+	req.Header.Set("Content-Type", "application/json")
+
+	client := &http.Client{Timeout: 30 * time.Second}
+	resp, err := client.Do(req)
+	if err != nil {
+		return fmt.Errorf("failed to call telegram: %w", redact(err, token))
+	}
+	defer resp.Body.Close()
+
+	var result struct {
+		OK          bool   `json:"ok"`
+		Description string `json:"description"`
+	}
+	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		return fmt.Errorf("failed to decode telegram response: %v", err)
+	}
+	if !result.OK {
+		return fmt.Errorf("telegram refused the message: %s", result.Description)
+	}
+	return nil
+}
+
+// redact removes the bot token from the URL in a request error, the token is
+// part of every Bot API URL
+func redact(err error, token string) error {
+	var urlErr *url.Error
+	if errors.As(err, &urlErr) {
+		urlErr.URL = strings.ReplaceAll(urlErr.URL, token, "<token>")
+	}
+	return err
+}
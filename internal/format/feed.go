@@ -0,0 +1,195 @@
+package format
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"time"
+)
+
+// Feed is a list of digests of one user, newest first
+type Feed struct {
+	Title   string
+	ID      string
+	Link    string
+	Updated time.Time
+	Digests []Digest
+}
+
+// ContentType returns the HTTP content type of a format
+func ContentType(format string) string {
+	switch format {
+	case Atom:
+		return "application/atom+xml; charset=utf-8"
+	case RSS:
+		return "application/rss+xml; charset=utf-8"
+	case JSONFeed:
+		return "application/feed+json; charset=utf-8"
+	case Markdown:
+		return "text/markdown; charset=utf-8"
+	case HTML:
+		return "text/html; charset=utf-8"
+	}
+	return "application/json; charset=utf-8"
+}
+
+// RenderFeed renders feed as Atom, RSS or JSON Feed
+func RenderFeed(format string, feed Feed) ([]byte, error) {
+	switch format {
+	case Atom:
+		return toAtom(feed)
+	case RSS:
+		return toRSS(feed)
+	case JSONFeed:
+		return toJSONFeed(feed)
+	}
+	return nil, fmt.Errorf("%q is not a feed format", format)
+}
+
+type atomFeed struct {
+	XMLName xml.Name    `xml:"http://www.w3.org/2005/Atom feed"`
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Updated string      `xml:"updated"`
+	Entries []atomEntry `xml:"entry"`
+}
+
+type atomLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+}
+
+type atomEntry struct {
+	Title   string      `xml:"title"`
+	ID      string      `xml:"id"`
+	Updated string      `xml:"updated"`
+	Link    *atomLink   `xml:"link,omitempty"`
+	Content atomContent `xml:"content"`
+}
+
+type atomContent struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+func toAtom(feed Feed) ([]byte, error) {
+	out := atomFeed{Title: feed.Title, ID: feed.ID, Updated: feed.Updated.UTC().Format(time.RFC3339)}
+	if feed.Link != "" {
+		out.Link = &atomLink{Href: feed.Link, Rel: "self"}
+	}
+	for _, digest := range feed.Digests {
+		body, err := ToHTML(digest)
+		if err != nil {
+			return nil, err
+		}
+		entry := atomEntry{
+			Title:   digest.Title,
+			ID:      "urn:digest:" + digest.ID,
+			Updated: digest.CreatedAt.UTC().Format(time.RFC3339),
+			Content: atomContent{Type: "html", Body: body},
+		}
+		if digest.LinkToVideo != "" {
+			entry.Link = &atomLink{Href: digest.LinkToVideo, Rel: "related"}
+		}
+		out.Entries = append(out.Entries, entry)
+	}
+	return marshalXML(out)
+}
+
+type rss struct {
+	XMLName xml.Name   `xml:"rss"`
+	Version string     `xml:"version,attr"`
+	Channel rssChannel `xml:"channel"`
+}
+
+type rssChannel struct {
+	Title         string    `xml:"title"`
+	Link          string    `xml:"link"`
+	Description   string    `xml:"description"`
+	LastBuildDate string    `xml:"lastBuildDate"`
+	Items         []rssItem `xml:"item"`
+}
+
+type rssItem struct {
+	Title       string  `xml:"title"`
+	Link        string  `xml:"link,omitempty"`
+	GUID        rssGUID `xml:"guid"`
+	PubDate     string  `xml:"pubDate"`
+	Description string  `xml:"description"`
+}
+
+type rssGUID struct {
+	IsPermaLink bool   `xml:"isPermaLink,attr"`
+	Value       string `xml:",chardata"`
+}
+
+func toRSS(feed Feed) ([]byte, error) {
+	out := rss{Version: "2.0", Channel: rssChannel{
+		Title:         feed.Title,
+		Link:          feed.Link,
+		Description:   feed.Title,
+		LastBuildDate: feed.Updated.UTC().Format(time.RFC1123Z),
+	}}
+	for _, digest := range feed.Digests {
+		body, err := ToHTML(digest)
+		if err != nil {
+			return nil, err
+		}
+		out.Channel.Items = append(out.Channel.Items, rssItem{
+			Title:       digest.Title,
+			Link:        digest.LinkToVideo,
+			GUID:        rssGUID{Value: "urn:digest:" + digest.ID},
+			PubDate:     digest.CreatedAt.UTC().Format(time.RFC1123Z),
+			Description: body,
+		})
+	}
+	return marshalXML(out)
+}
+
+func marshalXML(v interface{}) ([]byte, error) {
+	data, err := xml.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed: %v", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+type jsonFeed struct {
+	Version string         `json:"version"`
+	Title   string         `json:"title"`
+	FeedURL string         `json:"feed_url,omitempty"`
+	Items   []jsonFeedItem `json:"items"`
+}
+
+type jsonFeedItem struct {
+	ID            string `json:"id"`
+	Title         string `json:"title"`
+	ExternalURL   string `json:"external_url,omitempty"`
+	ContentHTML   string `json:"content_html"`
+	ContentText   string `json:"content_text"`
+	DatePublished string `json:"date_published"`
+}
+
+func toJSONFeed(feed Feed) ([]byte, error) {
+	out := jsonFeed{Version: "https://jsonfeed.org/version/1.1", Title: feed.Title, FeedURL: feed.Link, Items: []jsonFeedItem{}}
+	for _, digest := range feed.Digests {
+		body, err := ToHTML(digest)
+		if err != nil {
+			return nil, err
+		}
+		out.Items = append(out.Items, jsonFeedItem{
+			ID:            "urn:digest:" + digest.ID,
+			Title:         digest.Title,
+			ExternalURL:   digest.LinkToVideo,
+			ContentHTML:   body,
+			ContentText:   ToMarkdown(digest),
+			DatePublished: digest.CreatedAt.UTC().Format(time.RFC3339),
+		})
+	}
+	data, err := json.MarshalIndent(out, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal feed: %v", err)
+	}
+	return data, nil
+}
@@ -0,0 +1,138 @@
+package format
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"html/template"
+	"strings"
+	"time"
+)
+
+// Formats a digest can be rendered in, JSON is the legacy list of summaries
+const (
+	JSON     = "json"
+	Markdown = "markdown"
+	HTML     = "html"
+	Atom     = "atom"
+	RSS      = "rss"
+	JSONFeed = "jsonfeed"
+)
+
+// Digest is everything a formatter needs about one finished digest
+type Digest struct {
+	ID          string
+	Title       string
+	CreatedAt   time.Time
+	LinkToVideo string
+	Entries     []Entry
+}
+
+// Entry is the summary of one Instagram username
+type Entry struct {
+	Username string
+	Summary  string
+	Stories  []Story
+}
+
+type Story struct {
+	ID        string
+	Type      string
+	Link      string
+	Thumbnail string
+	TakenAt   time.Time
+	Summary   string
+}
+
+// legacy is the shape of the JSON result older clients parse, fields are
+// untagged like in openai.StoriesType so they marshal as Author and Summarize
+type legacy struct {
+	Author    string
+	Summarize string
+}
+
+// ProfileLink returns the Instagram profile of username
+func ProfileLink(username string) string {
+	return "https://www.instagram.com/" + username + "/"
+}
+
+// Valid reports whether format is known, empty is the default JSON
+func Valid(format string) bool {
+	switch format {
+	case "", JSON, Markdown, HTML, Atom, RSS, JSONFeed:
+		return true
+	}
+	return false
+}
+
+// Render formats a single digest, feed formats get a feed with only this digest
+func Render(format string, digest Digest) (string, error) {
+	switch format {
+	case "", JSON:
+		return toJSON(digest)
+	case Markdown:
+		return ToMarkdown(digest), nil
+	case HTML:
+		return ToHTML(digest)
+	case Atom, RSS, JSONFeed:
+		feed := Feed{Title: digest.Title, ID: "urn:digest:" + digest.ID, Updated: digest.CreatedAt, Digests: []Digest{digest}}
+		data, err := RenderFeed(format, feed)
+		return string(data), err
+	}
+	return "", fmt.Errorf("unknown format %q", format)
+}
+
+func toJSON(digest Digest) (string, error) {
+	stories := make([]legacy, 0, len(digest.Entries))
+	for _, entry := range digest.Entries {
+		stories = append(stories, legacy{Author: entry.Username, Summarize: entry.Summary})
+	}
+	data, err := json.Marshal(stories)
+	if err != nil {
+		return "", fmt.Errorf("failed to marshal digest: %v", err)
+	}
+	return string(data), nil
+}
+
+func ToMarkdown(digest Digest) string {
+	var b strings.Builder
+	fmt.Fprintf(&b, "# %s\n\n", digest.Title)
+	if digest.LinkToVideo != "" {
+		fmt.Fprintf(&b, "[Watch the recap video](%s)\n\n", digest.LinkToVideo)
+	}
+	for _, entry := range digest.Entries {
+		fmt.Fprintf(&b, "## [@%s](%s)\n\n%s\n\n", entry.Username, ProfileLink(entry.Username), entry.Summary)
+		for _, story := range entry.Stories {
+			fmt.Fprintf(&b, "- [%s](%s) %s\n", story.TakenAt.UTC().Format("15:04 MST"), story.Link, story.Summary)
+		}
+		if len(entry.Stories) > 0 {
+			b.WriteString("\n")
+		}
+	}
+	return b.String()
+}
+
+var htmlTemplate = template.Must(template.New("digest").Funcs(template.FuncMap{
+	"profile": ProfileLink,
+	"time":    func(t time.Time) string { return t.UTC().Format("15:04 MST") },
+}).Parse(`<!DOCTYPE html>
+<html>
+<head><meta charset="utf-8"><title>{{.Title}}</title></head>
+<body style="font-family: sans-serif; max-width: 640px; margin: 0 auto;">
+<h1>{{.Title}}</h1>
+{{if .LinkToVideo}}<p><a href="{{.LinkToVideo}}">Watch the recap video</a></p>
+{{end}}{{range .Entries}}<h2><a href="{{profile .Username}}">@{{.Username}}</a></h2>
+<p>{{.Summary}}</p>
+{{range .Stories}}<div style="margin-bottom: 12px;">{{if .Thumbnail}}<a href="{{.Link}}"><img src="{{.Thumbnail}}" alt="" width="96" style="float: left; margin-right: 12px;"></a>{{end}}<a href="{{.Link}}">{{time .TakenAt}}</a> {{.Summary}}<div style="clear: both;"></div></div>
+{{end}}{{end}}</body>
+</html>
+`))
+
+// ToHTML renders digest as an HTML document, usable as an email body
+func ToHTML(digest Digest) (string, error) {
+	var b bytes.Buffer
+	if err := htmlTemplate.Execute(&b, digest); err != nil {
+		return "", fmt.Errorf("failed to render HTML digest: %v", err)
+	}
+	return b.String(), nil
+}
@@ -21,6 +21,12 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	StoriesSummarizer_QueueLength_FullMethodName      = "/agent.StoriesSummarizer/QueueLength"
 	StoriesSummarizer_SummarizeStories_FullMethodName = "/agent.StoriesSummarizer/SummarizeStories"
+	StoriesSummarizer_GetJob_FullMethodName           = "/agent.StoriesSummarizer/GetJob"
+	StoriesSummarizer_ResumeJob_FullMethodName        = "/agent.StoriesSummarizer/ResumeJob"
+	StoriesSummarizer_CacheStats_FullMethodName       = "/agent.StoriesSummarizer/CacheStats"
+	StoriesSummarizer_GetDailyDigest_FullMethodName   = "/agent.StoriesSummarizer/GetDailyDigest"
+	StoriesSummarizer_GetHistory_FullMethodName       = "/agent.StoriesSummarizer/GetHistory"
+	StoriesSummarizer_SearchHistory_FullMethodName    = "/agent.StoriesSummarizer/SearchHistory"
 )
 
 // StoriesSummarizerClient is the client API for StoriesSummarizer service.
@@ -29,6 +35,12 @@ const (
 type StoriesSummarizerClient interface {
 	QueueLength(ctx context.Context, in *QueueLengthRequest, opts ...grpc.CallOption) (*QueueLengthResponse, error)
 	SummarizeStories(ctx context.Context, in *SummarizeStoriesRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SummarizeStoriesResponse], error)
+	GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error)
+	ResumeJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SummarizeStoriesResponse], error)
+	CacheStats(ctx context.Context, in *CacheStatsRequest, opts ...grpc.CallOption) (*CacheStatsResponse, error)
+	GetDailyDigest(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DailyDigestResponse, error)
+	GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
+	SearchHistory(ctx context.Context, in *SearchHistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error)
 }
 
 type storiesSummarizerClient struct {
@@ -68,12 +80,87 @@ func (c *storiesSummarizerClient) SummarizeStories(ctx context.Context, in *Summ
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type StoriesSummarizer_SummarizeStoriesClient = grpc.ServerStreamingClient[SummarizeStoriesResponse]
 
+func (c *storiesSummarizerClient) GetJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (*JobResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(JobResponse)
+	err := c.cc.Invoke(ctx, StoriesSummarizer_GetJob_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storiesSummarizerClient) ResumeJob(ctx context.Context, in *JobRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[SummarizeStoriesResponse], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &StoriesSummarizer_ServiceDesc.Streams[1], StoriesSummarizer_ResumeJob_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[JobRequest, SummarizeStoriesResponse]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StoriesSummarizer_ResumeJobClient = grpc.ServerStreamingClient[SummarizeStoriesResponse]
+
+func (c *storiesSummarizerClient) CacheStats(ctx context.Context, in *CacheStatsRequest, opts ...grpc.CallOption) (*CacheStatsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CacheStatsResponse)
+	err := c.cc.Invoke(ctx, StoriesSummarizer_CacheStats_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storiesSummarizerClient) GetDailyDigest(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*DailyDigestResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DailyDigestResponse)
+	err := c.cc.Invoke(ctx, StoriesSummarizer_GetDailyDigest_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storiesSummarizerClient) GetHistory(ctx context.Context, in *GetHistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, StoriesSummarizer_GetHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *storiesSummarizerClient) SearchHistory(ctx context.Context, in *SearchHistoryRequest, opts ...grpc.CallOption) (*HistoryResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(HistoryResponse)
+	err := c.cc.Invoke(ctx, StoriesSummarizer_SearchHistory_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // StoriesSummarizerServer is the server API for StoriesSummarizer service.
 // All implementations must embed UnimplementedStoriesSummarizerServer
 // for forward compatibility.
 type StoriesSummarizerServer interface {
 	QueueLength(context.Context, *QueueLengthRequest) (*QueueLengthResponse, error)
 	SummarizeStories(*SummarizeStoriesRequest, grpc.ServerStreamingServer[SummarizeStoriesResponse]) error
+	GetJob(context.Context, *JobRequest) (*JobResponse, error)
+	ResumeJob(*JobRequest, grpc.ServerStreamingServer[SummarizeStoriesResponse]) error
+	CacheStats(context.Context, *CacheStatsRequest) (*CacheStatsResponse, error)
+	GetDailyDigest(context.Context, *UserRequest) (*DailyDigestResponse, error)
+	GetHistory(context.Context, *GetHistoryRequest) (*HistoryResponse, error)
+	SearchHistory(context.Context, *SearchHistoryRequest) (*HistoryResponse, error)
 	mustEmbedUnimplementedStoriesSummarizerServer()
 }
 
@@ -90,6 +177,24 @@ func (UnimplementedStoriesSummarizerServer) QueueLength(context.Context, *QueueL
 func (UnimplementedStoriesSummarizerServer) SummarizeStories(*SummarizeStoriesRequest, grpc.ServerStreamingServer[SummarizeStoriesResponse]) error {
 	return status.Errorf(codes.Unimplemented, "method SummarizeStories not implemented")
 }
+func (UnimplementedStoriesSummarizerServer) GetJob(context.Context, *JobRequest) (*JobResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetJob not implemented")
+}
+func (UnimplementedStoriesSummarizerServer) ResumeJob(*JobRequest, grpc.ServerStreamingServer[SummarizeStoriesResponse]) error {
+	return status.Errorf(codes.Unimplemented, "method ResumeJob not implemented")
+}
+func (UnimplementedStoriesSummarizerServer) CacheStats(context.Context, *CacheStatsRequest) (*CacheStatsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CacheStats not implemented")
+}
+func (UnimplementedStoriesSummarizerServer) GetDailyDigest(context.Context, *UserRequest) (*DailyDigestResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetDailyDigest not implemented")
+}
+func (UnimplementedStoriesSummarizerServer) GetHistory(context.Context, *GetHistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetHistory not implemented")
+}
+func (UnimplementedStoriesSummarizerServer) SearchHistory(context.Context, *SearchHistoryRequest) (*HistoryResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method SearchHistory not implemented")
+}
 func (UnimplementedStoriesSummarizerServer) mustEmbedUnimplementedStoriesSummarizerServer() {}
 func (UnimplementedStoriesSummarizerServer) testEmbeddedByValue()                           {}
 
@@ -140,6 +245,107 @@ func _StoriesSummarizer_SummarizeStories_Handler(srv interface{}, stream grpc.Se
 // This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
 type StoriesSummarizer_SummarizeStoriesServer = grpc.ServerStreamingServer[SummarizeStoriesResponse]
 
+func _StoriesSummarizer_GetJob_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(JobRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoriesSummarizerServer).GetJob(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoriesSummarizer_GetJob_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoriesSummarizerServer).GetJob(ctx, req.(*JobRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoriesSummarizer_ResumeJob_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(JobRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(StoriesSummarizerServer).ResumeJob(m, &grpc.GenericServerStream[JobRequest, SummarizeStoriesResponse]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type StoriesSummarizer_ResumeJobServer = grpc.ServerStreamingServer[SummarizeStoriesResponse]
+
+func _StoriesSummarizer_CacheStats_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CacheStatsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoriesSummarizerServer).CacheStats(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoriesSummarizer_CacheStats_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoriesSummarizerServer).CacheStats(ctx, req.(*CacheStatsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoriesSummarizer_GetDailyDigest_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoriesSummarizerServer).GetDailyDigest(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoriesSummarizer_GetDailyDigest_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoriesSummarizerServer).GetDailyDigest(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoriesSummarizer_GetHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoriesSummarizerServer).GetHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoriesSummarizer_GetHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoriesSummarizerServer).GetHistory(ctx, req.(*GetHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _StoriesSummarizer_SearchHistory_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(SearchHistoryRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(StoriesSummarizerServer).SearchHistory(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: StoriesSummarizer_SearchHistory_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(StoriesSummarizerServer).SearchHistory(ctx, req.(*SearchHistoryRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // StoriesSummarizer_ServiceDesc is the grpc.ServiceDesc for StoriesSummarizer service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -151,6 +357,26 @@ var StoriesSummarizer_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "QueueLength",
 			Handler:    _StoriesSummarizer_QueueLength_Handler,
 		},
+		{
+			MethodName: "GetJob",
+			Handler:    _StoriesSummarizer_GetJob_Handler,
+		},
+		{
+			MethodName: "CacheStats",
+			Handler:    _StoriesSummarizer_CacheStats_Handler,
+		},
+		{
+			MethodName: "GetDailyDigest",
+			Handler:    _StoriesSummarizer_GetDailyDigest_Handler,
+		},
+		{
+			MethodName: "GetHistory",
+			Handler:    _StoriesSummarizer_GetHistory_Handler,
+		},
+		{
+			MethodName: "SearchHistory",
+			Handler:    _StoriesSummarizer_SearchHistory_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -158,6 +384,367 @@ var StoriesSummarizer_ServiceDesc = grpc.ServiceDesc{
 			Handler:       _StoriesSummarizer_SummarizeStories_Handler,
 			ServerStreams: true,
 		},
+		{
+			StreamName:    "ResumeJob",
+			Handler:       _StoriesSummarizer_ResumeJob_Handler,
+			ServerStreams: true,
+		},
+	},
+	Metadata: "proto/proto.proto",
+}
+
+const (
+	UsersAdmin_PutUser_FullMethodName  = "/agent.UsersAdmin/PutUser"
+	UsersAdmin_GetUser_FullMethodName  = "/agent.UsersAdmin/GetUser"
+	UsersAdmin_GetUsage_FullMethodName = "/agent.UsersAdmin/GetUsage"
+)
+
+// UsersAdminClient is the client API for UsersAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type UsersAdminClient interface {
+	PutUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error)
+	GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*User, error)
+	GetUsage(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserUsageResponse, error)
+}
+
+type usersAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewUsersAdminClient(cc grpc.ClientConnInterface) UsersAdminClient {
+	return &usersAdminClient{cc}
+}
+
+func (c *usersAdminClient) PutUser(ctx context.Context, in *User, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UsersAdmin_PutUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersAdminClient) GetUser(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*User, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(User)
+	err := c.cc.Invoke(ctx, UsersAdmin_GetUser_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *usersAdminClient) GetUsage(ctx context.Context, in *UserRequest, opts ...grpc.CallOption) (*UserUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(UserUsageResponse)
+	err := c.cc.Invoke(ctx, UsersAdmin_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// UsersAdminServer is the server API for UsersAdmin service.
+// All implementations must embed UnimplementedUsersAdminServer
+// for forward compatibility.
+type UsersAdminServer interface {
+	PutUser(context.Context, *User) (*User, error)
+	GetUser(context.Context, *UserRequest) (*User, error)
+	GetUsage(context.Context, *UserRequest) (*UserUsageResponse, error)
+	mustEmbedUnimplementedUsersAdminServer()
+}
+
+// UnimplementedUsersAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedUsersAdminServer struct{}
+
+func (UnimplementedUsersAdminServer) PutUser(context.Context, *User) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method PutUser not implemented")
+}
+func (UnimplementedUsersAdminServer) GetUser(context.Context, *UserRequest) (*User, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUser not implemented")
+}
+func (UnimplementedUsersAdminServer) GetUsage(context.Context, *UserRequest) (*UserUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
+func (UnimplementedUsersAdminServer) mustEmbedUnimplementedUsersAdminServer() {}
+func (UnimplementedUsersAdminServer) testEmbeddedByValue()                    {}
+
+// UnsafeUsersAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to UsersAdminServer will
+// result in compilation errors.
+type UnsafeUsersAdminServer interface {
+	mustEmbedUnimplementedUsersAdminServer()
+}
+
+func RegisterUsersAdminServer(s grpc.ServiceRegistrar, srv UsersAdminServer) {
+	// If the following call pancis, it indicates UnimplementedUsersAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&UsersAdmin_ServiceDesc, srv)
+}
+
+func _UsersAdmin_PutUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(User)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersAdminServer).PutUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersAdmin_PutUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersAdminServer).PutUser(ctx, req.(*User))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersAdmin_GetUser_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersAdminServer).GetUser(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersAdmin_GetUser_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersAdminServer).GetUser(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _UsersAdmin_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(UserRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(UsersAdminServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: UsersAdmin_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(UsersAdminServer).GetUsage(ctx, req.(*UserRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// UsersAdmin_ServiceDesc is the grpc.ServiceDesc for UsersAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var UsersAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "agent.UsersAdmin",
+	HandlerType: (*UsersAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "PutUser",
+			Handler:    _UsersAdmin_PutUser_Handler,
+		},
+		{
+			MethodName: "GetUser",
+			Handler:    _UsersAdmin_GetUser_Handler,
+		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _UsersAdmin_GetUsage_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/proto.proto",
+}
+
+const (
+	AccountsAdmin_ListAccounts_FullMethodName = "/agent.AccountsAdmin/ListAccounts"
+	AccountsAdmin_StartLogin_FullMethodName   = "/agent.AccountsAdmin/StartLogin"
+	AccountsAdmin_VerifyLogin_FullMethodName  = "/agent.AccountsAdmin/VerifyLogin"
+)
+
+// AccountsAdminClient is the client API for AccountsAdmin service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+type AccountsAdminClient interface {
+	ListAccounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error)
+	StartLogin(ctx context.Context, in *StartLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+	VerifyLogin(ctx context.Context, in *VerifyLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error)
+}
+
+type accountsAdminClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAccountsAdminClient(cc grpc.ClientConnInterface) AccountsAdminClient {
+	return &accountsAdminClient{cc}
+}
+
+func (c *accountsAdminClient) ListAccounts(ctx context.Context, in *AccountsRequest, opts ...grpc.CallOption) (*AccountsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AccountsResponse)
+	err := c.cc.Invoke(ctx, AccountsAdmin_ListAccounts_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsAdminClient) StartLogin(ctx context.Context, in *StartLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, AccountsAdmin_StartLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *accountsAdminClient) VerifyLogin(ctx context.Context, in *VerifyLoginRequest, opts ...grpc.CallOption) (*LoginResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(LoginResponse)
+	err := c.cc.Invoke(ctx, AccountsAdmin_VerifyLogin_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AccountsAdminServer is the server API for AccountsAdmin service.
+// All implementations must embed UnimplementedAccountsAdminServer
+// for forward compatibility.
+type AccountsAdminServer interface {
+	ListAccounts(context.Context, *AccountsRequest) (*AccountsResponse, error)
+	StartLogin(context.Context, *StartLoginRequest) (*LoginResponse, error)
+	VerifyLogin(context.Context, *VerifyLoginRequest) (*LoginResponse, error)
+	mustEmbedUnimplementedAccountsAdminServer()
+}
+
+// UnimplementedAccountsAdminServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAccountsAdminServer struct{}
+
+func (UnimplementedAccountsAdminServer) ListAccounts(context.Context, *AccountsRequest) (*AccountsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ListAccounts not implemented")
+}
+func (UnimplementedAccountsAdminServer) StartLogin(context.Context, *StartLoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method StartLogin not implemented")
+}
+func (UnimplementedAccountsAdminServer) VerifyLogin(context.Context, *VerifyLoginRequest) (*LoginResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method VerifyLogin not implemented")
+}
+func (UnimplementedAccountsAdminServer) mustEmbedUnimplementedAccountsAdminServer() {}
+func (UnimplementedAccountsAdminServer) testEmbeddedByValue()                       {}
+
+// UnsafeAccountsAdminServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AccountsAdminServer will
+// result in compilation errors.
+type UnsafeAccountsAdminServer interface {
+	mustEmbedUnimplementedAccountsAdminServer()
+}
+
+func RegisterAccountsAdminServer(s grpc.ServiceRegistrar, srv AccountsAdminServer) {
+	// If the following call pancis, it indicates UnimplementedAccountsAdminServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AccountsAdmin_ServiceDesc, srv)
+}
+
+func _AccountsAdmin_ListAccounts_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AccountsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsAdminServer).ListAccounts(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountsAdmin_ListAccounts_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsAdminServer).ListAccounts(ctx, req.(*AccountsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountsAdmin_StartLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(StartLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsAdminServer).StartLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountsAdmin_StartLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsAdminServer).StartLogin(ctx, req.(*StartLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AccountsAdmin_VerifyLogin_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(VerifyLoginRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AccountsAdminServer).VerifyLogin(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AccountsAdmin_VerifyLogin_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AccountsAdminServer).VerifyLogin(ctx, req.(*VerifyLoginRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AccountsAdmin_ServiceDesc is the grpc.ServiceDesc for AccountsAdmin service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AccountsAdmin_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "agent.AccountsAdmin",
+	HandlerType: (*AccountsAdminServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "ListAccounts",
+			Handler:    _AccountsAdmin_ListAccounts_Handler,
+		},
+		{
+			MethodName: "StartLogin",
+			Handler:    _AccountsAdmin_StartLogin_Handler,
+		},
+		{
+			MethodName: "VerifyLogin",
+			Handler:    _AccountsAdmin_VerifyLogin_Handler,
+		},
 	},
+	Streams:  []grpc.StreamDesc{},
 	Metadata: "proto/proto.proto",
 }
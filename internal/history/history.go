@@ -0,0 +1,188 @@
+package history
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"strings"
+	"time"
+)
+
+// Version of the stored history schema, older versions are migrated when read
+const Version = 1
+
+var ErrUnsupportedVersion = errors.New("unsupported history version")
+
+const (
+	// DateLayout is how record dates are written
+	DateLayout = "2006-01-02"
+	// legacyDateLayout is the date appended to summaries before records existed
+	legacyDateLayout = "02.01.2006"
+	// legacyDays is how long history was kept before retention was configurable
+	legacyDays = 7
+	// contextDays of history are given to prompts
+	contextDays = 7
+)
+
+// Record is the summary of the stories a username posted on Date
+type Record struct {
+	Date       string    `json:"date"`
+	Summary    string    `json:"summary"`
+	StoryIDs   []string  `json:"storyIds"`
+	MediaTypes []string  `json:"mediaTypes"`
+	Tags       []string  `json:"tags"`
+	CreatedAt  time.Time `json:"createdAt"`
+}
+
+// History is the recent records of a username, oldest first
+type History struct {
+	Version  int      `json:"version"`
+	Username string   `json:"username"`
+	Records  []Record `json:"records"`
+}
+
+// Get returns the history of username, empty when there is none. History in
+// the legacy format is migrated and saved back.
+func Get(ctx context.Context, username string) (*History, error) {
+	value, err := redis.GetHistory(ctx, username)
+	if errors.Is(err, redis.ErrNotExist) {
+		return legacy(ctx, username)
+	} else if err != nil {
+		return nil, err
+	}
+	var h History
+	if err = json.Unmarshal([]byte(value), &h); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal history of %s: %v", username, err)
+	}
+	if h.Version != Version {
+		return nil, fmt.Errorf("%w: %d", ErrUnsupportedVersion, h.Version)
+	}
+	return &h, nil
+}
+
+// legacy reads history from the format of a JSON list of "summary dd.mm.yyyy" strings
+func legacy(ctx context.Context, username string) (*History, error) {
+	h := &History{Version: Version, Username: username, Records: []Record{}}
+	value, err := redis.GetLegacyHistory(ctx, username)
+	if errors.Is(err, redis.ErrNotExist) {
+		return h, nil
+	} else if err != nil {
+		return nil, err
+	}
+	var entries []string
+	if err = json.Unmarshal([]byte(value), &entries); err != nil {
+		// Not a history list, nothing to migrate
+		return h, nil
+	}
+	for _, entry := range entries {
+		h.Records = append(h.Records, migrate(entry))
+	}
+
+	if err = Save(ctx, h, legacyDays); err != nil {
+		return nil, err
+	}
+	if err = redis.DeleteLegacyHistory(ctx, username); err != nil {
+		logger.Error("Error deleting migrated history", zap.String("username", username), zap.Error(err))
+	}
+	logger.Info("Migrated history", zap.String("username", username), zap.Int("records", len(h.Records)))
+	return h, nil
+}
+
+// migrate splits a legacy entry into its summary and the date at its end
+func migrate(entry string) Record {
+	if len(entry) > len(legacyDateLayout) {
+		summary := entry[:len(entry)-len(legacyDateLayout)]
+		if date, err := time.Parse(legacyDateLayout, entry[len(entry)-len(legacyDateLayout):]); err == nil {
+			return Record{Date: date.Format(DateLayout), Summary: strings.TrimSpace(summary), CreatedAt: date}
+		}
+	}
+	return Record{Summary: entry}
+}
+
+// Save stores h for days, records older than that are dropped
+func Save(ctx context.Context, h *History, days int) error {
+	h.Version = Version
+	h.prune(days, time.Now())
+	value, err := json.Marshal(h)
+	if err != nil {
+		return fmt.Errorf("failed to marshal history of %s: %v", h.Username, err)
+	}
+	return redis.StoreHistory(ctx, h.Username, string(value), time.Duration(days)*24*time.Hour)
+}
+
+// prune drops dated records older than days before now
+func (h *History) prune(days int, now time.Time) {
+	oldest := Since(days, now)
+	records := make([]Record, 0, len(h.Records))
+	for _, record := range h.Records {
+		if record.Date == "" || record.Date >= oldest {
+			records = append(records, record)
+		}
+	}
+	h.Records = records
+}
+
+// Since returns the date of the oldest day in the last days before now
+func Since(days int, now time.Time) string {
+	return now.AddDate(0, 0, -(days - 1)).Format(DateLayout)
+}
+
+// Between returns records dated from to to, both included, newest first.
+// Empty bounds are open, undated records are only returned without bounds.
+func (h *History) Between(from string, to string) []Record {
+	result := make([]Record, 0, len(h.Records))
+	for i := len(h.Records) - 1; i >= 0; i-- {
+		record := h.Records[i]
+		if (from != "" || to != "") && record.Date == "" {
+			continue
+		}
+		if (from != "" && record.Date < from) || (to != "" && record.Date > to) {
+			continue
+		}
+		result = append(result, record)
+	}
+	return result
+}
+
+// Has reports whether there is a record for date
+func (h *History) Has(date string) bool {
+	for _, record := range h.Records {
+		if record.Date == date {
+			return true
+		}
+	}
+	return false
+}
+
+// Add appends record unless its date already has one, it reports whether record was added
+func (h *History) Add(record Record) bool {
+	if h.Has(record.Date) {
+		return false
+	}
+	h.Records = append(h.Records, record)
+	return true
+}
+
+// Context is the last days of history as prompts get it, one dated summary per line
+func (h *History) Context() string {
+	oldest := Since(contextDays, time.Now())
+	var b strings.Builder
+	for _, record := range h.Records {
+		if record.Date != "" && record.Date < oldest {
+			continue
+		}
+		if record.Date != "" {
+			b.WriteString(record.Date + ": ")
+		}
+		b.WriteString(record.Summary)
+		if len(record.Tags) > 0 {
+			b.WriteString(" (" + strings.Join(record.Tags, ", ") + ")")
+		}
+		b.WriteString("\n")
+	}
+	return b.String()
+}
@@ -0,0 +1,110 @@
+package inst
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/Davincible/goinsta"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"sync"
+	"time"
+)
+
+var ErrNoPendingLogin = errors.New("no pending login for account")
+
+const (
+	PendingChallenge  = "challenge"
+	PendingTwoFactor  = "two_factor"
+	PendingCheckpoint = "checkpoint"
+)
+
+// pendingLogin is a login stopped by Instagram, the client is kept until an
+// admin sends the verification code
+type pendingLogin struct {
+	kind  string
+	insta *goinsta.Instagram
+	since time.Time
+}
+
+var pending = struct {
+	mu     sync.Mutex
+	logins map[string]*pendingLogin
+}{logins: make(map[string]*pendingLogin)}
+
+// park keeps insta of a login that failed with a challenge or 2FA request. For a
+// challenge Instagram is asked to send the security code right away.
+func park(ctx context.Context, login string, insta *goinsta.Instagram, err error) {
+	var kind string
+	switch {
+	case errors.Is(err, goinsta.Err2FARequired):
+		kind = PendingTwoFactor
+	case errors.Is(err, goinsta.ErrChallengeRequired):
+		kind = PendingChallenge
+		if insta.Challenge != nil {
+			if err := insta.Challenge.ProcessOld(insta.Challenge.ApiPath); err != nil {
+				logger.Error("Failed to request Instagram security code", zap.String("login", login), zap.Error(err))
+			}
+		}
+	case errors.Is(err, goinsta.ErrCheckpointRequired), errors.Is(err, goinsta.ErrChallengeFailed):
+		kind = PendingCheckpoint
+	default:
+		return
+	}
+
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	pending.logins[login] = &pendingLogin{kind: kind, insta: insta, since: time.Now()}
+	logger.Info("Instagram login is waiting for verification", zap.String("login", login), zap.String("kind", kind))
+}
+
+// Pending returns the kind of verification login waits for, empty if none
+func Pending(login string) string {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	if p, ok := pending.logins[login]; ok {
+		return p.kind
+	}
+	return ""
+}
+
+// Verify finishes a parked login with the code sent by Instagram and stores the session
+func Verify(ctx context.Context, login string, code string) error {
+	pending.mu.Lock()
+	p, ok := pending.logins[login]
+	pending.mu.Unlock()
+	if !ok {
+		return fmt.Errorf("%w: %s", ErrNoPendingLogin, login)
+	}
+
+	insta := p.insta
+	withContext(ctx, insta)
+	var err error
+	switch p.kind {
+	case PendingTwoFactor:
+		err = insta.TwoFactorInfo.Login2FA(code)
+	case PendingChallenge:
+		err = insta.Challenge.SendSecurityCode(code)
+		if err == nil {
+			insta.Account = insta.Challenge.LoggedInUser
+			err = insta.OpenApp()
+		}
+	default:
+		return fmt.Errorf("%w: %s has to be passed in the Instagram app, then the login started again", ErrChallengeRequired, p.kind)
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %w", ErrLoginFailed, err)
+	}
+
+	pending.mu.Lock()
+	delete(pending.logins, login)
+	pending.mu.Unlock()
+	saveSession(ctx, login, insta)
+	return nil
+}
+
+func forget(login string) {
+	pending.mu.Lock()
+	defer pending.mu.Unlock()
+	delete(pending.logins, login)
+}
@@ -0,0 +1,213 @@
+package inst
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Davincible/goinsta"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"os"
+	"path/filepath"
+	"strconv"
+	"sync"
+	"time"
+)
+
+const (
+	Image = "image"
+	Video = "video"
+)
+
+// Profile is an Instagram profile with its current stories
+type Profile struct {
+	Username   string  `json:"username"`
+	IsBusiness bool    `json:"isBusiness"`
+	FollowedBy bool    `json:"followedBy"`
+	Stories    []Story `json:"stories"`
+}
+
+type Story struct {
+	ID        string        `json:"id"`
+	Username  string        `json:"username"`
+	TakenAt   time.Time     `json:"takenAt"`
+	Media     Media         `json:"media"`
+	Events    []interface{} `json:"events"`
+	Hashtags  []interface{} `json:"hashtags"`
+	Polls     []interface{} `json:"polls"`
+	Locations []interface{} `json:"locations"`
+	Sliders   []interface{} `json:"sliders"`
+	Questions []interface{} `json:"questions"`
+	Mentions  []string      `json:"mentions"`
+}
+
+// Tags returns mentions and hashtags of the story as @name and #name
+func (s Story) Tags() []string {
+	tags := make([]string, 0, len(s.Mentions)+len(s.Hashtags))
+	for _, mention := range s.Mentions {
+		tags = append(tags, "@"+mention)
+	}
+	for _, hashtag := range s.Hashtags {
+		sticker, _ := hashtag.(map[string]interface{})
+		tag, _ := sticker["hashtag"].(map[string]interface{})
+		if name, ok := tag["name"].(string); ok && name != "" {
+			tags = append(tags, "#"+name)
+		}
+	}
+	return tags
+}
+
+// Media is the one version of a story that gets summarized, the best video or
+// the first image version. ID is the media PK, it stays the same while the
+// signed URL changes on every fetch.
+type Media struct {
+	ID        string `json:"id"`
+	Version   string `json:"version"`
+	Type      string `json:"type"`
+	URL       string `json:"url"`
+	Thumbnail string `json:"thumbnail"`
+}
+
+// StoryFetcher gives profiles with stories to the summarize pipeline. A fetcher
+// is created for every job.
+type StoryFetcher interface {
+	// Login prepares the fetcher, it is called once before the first Fetch
+	Login(ctx context.Context) error
+	Fetch(ctx context.Context, username string) (*Profile, error)
+}
+
+// IsLoginError reports whether err means the fetcher can not be used anymore,
+// other Fetch errors only concern a single profile
+func IsLoginError(err error) bool {
+	return errors.Is(err, ErrLoginFailed) || errors.Is(err, ErrChallengeRequired) || errors.Is(err, ErrNoAccounts)
+}
+
+// InstagramFetcher fetches stories with accounts of the pool. Fetches are
+// serialized, a session and its goinsta client are not safe for concurrent use.
+type InstagramFetcher struct {
+	mu      sync.Mutex
+	session *Session
+}
+
+func (p *Pool) Fetcher() StoryFetcher {
+	return &InstagramFetcher{session: p.Session()}
+}
+
+func (f *InstagramFetcher) Login(ctx context.Context) error {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	_, err := f.session.Instagram(ctx)
+	return err
+}
+
+func (f *InstagramFetcher) Fetch(ctx context.Context, username string) (*Profile, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	insta, err := f.session.Instagram(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	visited, err := insta.VisitProfile(username)
+	if err != nil {
+		f.session.Report(err)
+		return nil, fmt.Errorf("failed to visit profile %s: %w", username, err)
+	}
+	logger.Info("Visited profile for user", zap.String("username", username), zap.String("profile", visited.User.Username))
+
+	media, err := visited.User.Stories()
+	f.session.Report(err)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch stories of %s: %w", username, err)
+	}
+
+	profile := &Profile{
+		Username:   visited.User.Username,
+		IsBusiness: visited.User.IsBusiness,
+		FollowedBy: visited.User.Friendship.FollowedBy,
+		Stories:    make([]Story, 0, len(media.Reel.Items)),
+	}
+	for _, item := range media.Reel.Items {
+		profile.Stories = append(profile.Stories, toStory(item))
+	}
+
+	if dir := config.Config.RecordFixtures; dir != "" {
+		if err = Record(dir, profile); err != nil {
+			logger.Error("Error recording fixture", zap.String("username", username), zap.Error(err))
+		}
+	}
+	return profile, nil
+}
+
+func toStory(item *goinsta.Item) Story {
+	story := Story{
+		ID:        strconv.FormatInt(item.Pk, 10),
+		Username:  item.User.Username,
+		TakenAt:   time.Unix(item.TakenAt, 0),
+		Events:    item.StoryEvents,
+		Hashtags:  item.StoryHashtags,
+		Polls:     item.StoryPolls,
+		Locations: item.StoryLocations,
+		Sliders:   item.StorySliders,
+		Questions: item.StoryQuestions,
+		Mentions:  make([]string, 0, len(item.Mentions)),
+	}
+	for _, mention := range item.Mentions {
+		story.Mentions = append(story.Mentions, mention.User.Username)
+	}
+	if len(item.Videos) > 0 {
+		video := item.Videos[0]
+		story.Media = Media{ID: story.ID, Version: fmt.Sprintf("%d-%s", video.Type, video.ID), Type: Video, URL: video.URL}
+	} else if len(item.Images.Versions) > 0 {
+		image := item.Images.Versions[0]
+		story.Media = Media{ID: story.ID, Version: fmt.Sprintf("%dx%d", image.Width, image.Height), Type: Image, URL: image.URL}
+	}
+	// Videos have their cover among the image versions
+	if len(item.Images.Versions) > 0 {
+		story.Media.Thumbnail = item.Images.Versions[0].URL
+	}
+	return story
+}
+
+// FixtureFetcher reads profiles from JSON files named <username>.json in Dir,
+// in the format written by Record. It never touches the network.
+type FixtureFetcher struct {
+	Dir string
+}
+
+func (f FixtureFetcher) Login(ctx context.Context) error {
+	return ctx.Err()
+}
+
+func (f FixtureFetcher) Fetch(ctx context.Context, username string) (*Profile, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	value, err := os.ReadFile(fixturePath(f.Dir, username))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read fixture of %s: %w", username, err)
+	}
+	var profile Profile
+	if err = json.Unmarshal(value, &profile); err != nil {
+		return nil, fmt.Errorf("failed to parse fixture of %s: %w", username, err)
+	}
+	return &profile, nil
+}
+
+// Record writes profile as a fixture FixtureFetcher can read
+func Record(dir string, profile *Profile) error {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return err
+	}
+	value, err := json.MarshalIndent(profile, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(fixturePath(dir, profile.Username), value, 0o644)
+}
+
+func fixturePath(dir string, username string) string {
+	return filepath.Join(dir, filepath.Base(username)+".json")
+}
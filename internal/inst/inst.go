@@ -2,49 +2,165 @@ package inst
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"github.com/Davincible/goinsta"
 	"github.com/rendizi/stay-connected-inst/internal/redis"
 	"github.com/rendizi/stay-connected-inst/pkg/logger"
-	"log"
+	"go.uber.org/zap"
+	"net/http"
+	"time"
 )
 
-func Login(login string, password string) (*goinsta.Instagram, error) {
+var (
+	ErrLoginFailed       = errors.New("failed to login to Instagram")
+	ErrChallengeRequired = errors.New("Instagram requires a challenge")
+)
+
+// loginError wraps err of a failed login with ErrChallengeRequired or ErrLoginFailed,
+// the goinsta error stays available to errors.Is
+func loginError(err error) error {
+	if errors.Is(err, goinsta.ErrChallengeRequired) || errors.Is(err, goinsta.ErrCheckpointRequired) ||
+		errors.Is(err, goinsta.ErrChallengeFailed) || errors.Is(err, goinsta.Err2FARequired) {
+		return fmt.Errorf("%w: %w", ErrChallengeRequired, err)
+	}
+	return fmt.Errorf("%w: %w", ErrLoginFailed, err)
+}
+
+// contextTransport binds every Instagram request to ctx, goinsta itself has no context support
+type contextTransport struct {
+	ctx  context.Context
+	base http.RoundTripper
+}
+
+func (t contextTransport) RoundTrip(req *http.Request) (*http.Response, error) {
+	return t.base.RoundTrip(req.WithContext(t.ctx))
+}
+
+func withContext(ctx context.Context, insta *goinsta.Instagram) {
+	insta.SetHTTPTransport(contextTransport{ctx: ctx, base: &http.Transport{Proxy: http.ProxyFromEnvironment}})
+}
+
+// Login returns Instagram client which requests are cancelled when ctx is done.
+// A stored session is reused when Instagram still accepts it, every successful
+// login is saved back to the cookie store.
+func Login(ctx context.Context, login string, password string) (*goinsta.Instagram, error) {
 	var err error
 	var instaCookies string
 	var insta *goinsta.Instagram
 
-	instaCookies, err = redis.GetCookies(context.Background(), login)
+	instaCookies, err = redis.GetCookies(ctx, login)
 	if err != nil {
 		insta = goinsta.New(login, password)
+		withContext(ctx, insta)
 		err = insta.Login()
 		if err != nil {
-			logger.Error(fmt.Sprintf("Failed to login to instagram: %w", err))
-			log.Fatalf("failed to login to Instagram: %w", err)
-			return nil, fmt.Errorf("failed to login to Instagram: %w", err)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Error("Failed to login to instagram", zap.String("login", login), zap.Error(err))
+			park(ctx, login, insta, err)
+			return nil, loginError(err)
 		}
 		logger.Info("Logged in successfully")
+		saveSession(ctx, login, insta)
 		return insta, nil
 	}
 
 	insta, err = goinsta.ImportFromBase64String(instaCookies)
 	if err != nil {
+		InvalidateSession(ctx, login)
 		return nil, fmt.Errorf("failed to parse Instagram cookies: %w", err)
 	}
 
+	withContext(ctx, insta)
 	err = insta.OpenApp()
 	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		InvalidateSession(ctx, login)
 		insta = goinsta.New(login, password)
+		withContext(ctx, insta)
 		err = insta.Login()
 		if err != nil {
-			log.Fatalf("failed to re-login to Instagram: %w", err)
-			return nil, fmt.Errorf("failed to re-login to Instagram: %w", err)
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			logger.Error("Failed to re-login to instagram", zap.String("login", login), zap.Error(err))
+			park(ctx, login, insta, err)
+			return nil, loginError(err)
 		}
 	}
+	saveSession(ctx, login, insta)
 
 	return insta, nil
 }
 
-func EntryContainsDate(entry, date string) bool {
-	return len(entry) > 10 && entry[len(entry)-10:] == date
+// saveSession exports the session of insta to the cookie store, failures are only logged
+func saveSession(ctx context.Context, login string, insta *goinsta.Instagram) {
+	cookies, err := insta.ExportAsBase64String()
+	if err != nil {
+		logger.Error("Failed to export Instagram session", zap.String("login", login), zap.Error(err))
+		return
+	}
+	if err = redis.StoreCookies(ctx, login, cookies); err != nil {
+		logger.Error("Failed to store Instagram session", zap.String("login", login), zap.Error(err))
+	}
+}
+
+// InvalidateSession removes the stored session of login, the next Login uses the password
+func InvalidateSession(ctx context.Context, login string) {
+	if err := redis.DeleteCookies(ctx, login); err != nil {
+		logger.Error("Failed to invalidate Instagram session", zap.String("login", login), zap.Error(err))
+	}
+}
+
+// IsSessionRejected reports whether err means Instagram does not accept the session anymore
+func IsSessionRejected(err error) bool {
+	return errors.Is(err, goinsta.ErrLoggedOut) || errors.Is(err, goinsta.ErrLoginRequired)
+}
+
+// RefreshSessions opens the app with every stored session once per interval so
+// sessions stay fresh and get saved again. Rejected sessions are invalidated.
+func RefreshSessions(ctx context.Context, pool *Pool, interval time.Duration) {
+	if interval <= 0 {
+		return
+	}
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+		for _, account := range pool.Accounts() {
+			if err := refreshSession(ctx, account.Login); err != nil {
+				logger.Error("Failed to refresh Instagram session", zap.String("login", account.Login), zap.Error(err))
+			}
+		}
+	}
+}
+
+func refreshSession(ctx context.Context, login string) error {
+	cookies, err := redis.GetCookies(ctx, login)
+	if err != nil {
+		// Nothing stored, the account logs in with password when it is used
+		return nil
+	}
+	insta, err := goinsta.ImportFromBase64String(cookies)
+	if err != nil {
+		InvalidateSession(ctx, login)
+		return fmt.Errorf("failed to parse Instagram cookies: %w", err)
+	}
+	withContext(ctx, insta)
+	if err = insta.OpenApp(); err != nil {
+		if ctx.Err() == nil {
+			InvalidateSession(ctx, login)
+		}
+		return err
+	}
+	saveSession(ctx, login, insta)
+	return nil
 }
@@ -0,0 +1,335 @@
+package inst
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/Davincible/goinsta"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"net"
+	"strings"
+	"sync"
+	"time"
+)
+
+var ErrNoAccounts = errors.New("no healthy Instagram accounts available")
+
+const (
+	// maxFailures in a row put an account on cooldown
+	maxFailures      = 3
+	failureCooldown  = 30 * time.Minute
+	rateLimitBackoff = 15 * time.Minute
+	maxCooldown      = 24 * time.Hour
+)
+
+// Health is what the pool knows about an account, it is kept in Redis between restarts
+type Health struct {
+	Failures      int       `json:"failures"`
+	Cooldowns     int       `json:"cooldowns"`
+	CooldownUntil time.Time `json:"cooldownUntil"`
+	Checkpoint    bool      `json:"checkpoint"`
+	LastError     string    `json:"lastError"`
+	LastUsed      time.Time `json:"lastUsed"`
+}
+
+type Account struct {
+	Login    string
+	Password string
+	Health   Health
+}
+
+func (a *Account) healthy(now time.Time) bool {
+	return !a.Health.Checkpoint && !now.Before(a.Health.CooldownUntil)
+}
+
+// Pool hands out Instagram accounts in round robin order, skipping accounts
+// that are on cooldown or stuck on a checkpoint
+type Pool struct {
+	mu       sync.Mutex
+	accounts []*Account
+	next     int
+}
+
+// NewPool loads accounts from config and Redis together with their health
+func NewPool(ctx context.Context) (*Pool, error) {
+	credentials := make(map[string]string)
+	logins := make([]string, 0)
+	add := func(login string, password string) {
+		if login == "" {
+			return
+		}
+		if _, ok := credentials[login]; !ok {
+			logins = append(logins, login)
+		}
+		credentials[login] = password
+	}
+
+	add(config.Config.DefaultLogin, config.Config.DefaultPassword)
+	for _, entry := range strings.Split(config.Config.InstagramAccounts, ",") {
+		login, password, ok := strings.Cut(strings.TrimSpace(entry), ":")
+		if ok {
+			add(login, password)
+		}
+	}
+	stored, err := redis.GetAccounts(ctx)
+	if err != nil {
+		logger.Error("Error loading Instagram accounts from Redis", zap.Error(err))
+	}
+	for login, password := range stored {
+		add(login, password)
+	}
+	if len(logins) == 0 {
+		return nil, ErrNoAccounts
+	}
+
+	p := &Pool{}
+	for _, login := range logins {
+		account := &Account{Login: login, Password: credentials[login]}
+		if health, err := redis.GetAccountHealth(ctx, login); err == nil {
+			if err = json.Unmarshal([]byte(health), &account.Health); err != nil {
+				logger.Error("Error unmarshalling account health", zap.String("login", login), zap.Error(err))
+			}
+		}
+		p.accounts = append(p.accounts, account)
+	}
+	return p, nil
+}
+
+// Acquire returns the next healthy account
+func (p *Pool) Acquire() (*Account, error) {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+
+	now := time.Now()
+	for i := 0; i < len(p.accounts); i++ {
+		account := p.accounts[(p.next+i)%len(p.accounts)]
+		if account.healthy(now) {
+			p.next = (p.next + i + 1) % len(p.accounts)
+			account.Health.LastUsed = now
+			return account, nil
+		}
+	}
+	return nil, ErrNoAccounts
+}
+
+// Report records the outcome of an Instagram call made with account and saves
+// the health of the account. A nil error resets failures, login, checkpoint,
+// rate limit and transport errors may take the account out of rotation.
+func (p *Pool) Report(account *Account, err error) {
+	p.mu.Lock()
+	now := time.Now()
+	health := &account.Health
+	switch {
+	case err == nil:
+		health.Failures = 0
+		health.Cooldowns = 0
+	case errors.Is(err, context.Canceled), errors.Is(err, context.DeadlineExceeded):
+	case isProfileError(err):
+		// an unknown, private or deleted username says nothing about the account
+	case errors.Is(err, goinsta.ErrChallengeRequired), errors.Is(err, goinsta.ErrCheckpointRequired),
+		errors.Is(err, goinsta.ErrChallengeFailed), errors.Is(err, goinsta.Err2FARequired):
+		health.Checkpoint = true
+		health.LastError = err.Error()
+		logger.Error("Instagram account needs a checkpoint, removed from rotation", zap.String("login", account.Login), zap.Error(err))
+	case errors.Is(err, goinsta.ErrTooManyRequests):
+		health.LastError = err.Error()
+		p.cooldown(account, rateLimitBackoff, now)
+	case isAccountFailure(err):
+		health.Failures++
+		health.LastError = err.Error()
+		if health.Failures >= maxFailures {
+			health.Failures = 0
+			p.cooldown(account, failureCooldown, now)
+		}
+	default:
+		health.LastError = err.Error()
+	}
+	value, _ := json.Marshal(health)
+	p.mu.Unlock()
+
+	if err := redis.StoreAccountHealth(context.Background(), account.Login, string(value)); err != nil {
+		logger.Error("Error storing account health", zap.String("login", account.Login), zap.Error(err))
+	}
+}
+
+// isProfileError reports whether err only concerns the visited profile, like an
+// unknown, private or deleted username
+func isProfileError(err error) bool {
+	if errors.Is(err, goinsta.ErrSearchUserNotFound) || errors.Is(err, goinsta.ErrMediaDeleted) {
+		return true
+	}
+	// VisitProfile has no sentinel for a search without an exact match
+	if strings.Contains(err.Error(), "Profile not found") {
+		return true
+	}
+	var notFound goinsta.ErrorN
+	if errors.As(err, &notFound) && notFound.Status == "404" {
+		return true
+	}
+	var refused goinsta.Error400
+	if errors.As(err, &refused) {
+		message := strings.ToLower(refused.Message)
+		return strings.Contains(message, "not authorized to view user") || strings.Contains(message, "user not found")
+	}
+	return false
+}
+
+// isAccountFailure reports whether err means the account could not log in or
+// Instagram could not be reached with it
+func isAccountFailure(err error) bool {
+	if errors.Is(err, ErrLoginFailed) || errors.Is(err, goinsta.ErrBadPassword) {
+		return true
+	}
+	var unavailable goinsta.Error503
+	var server goinsta.ErrorN
+	var netErr net.Error
+	return errors.As(err, &unavailable) || errors.As(err, &netErr) ||
+		(errors.As(err, &server) && strings.HasPrefix(server.Status, "5"))
+}
+
+// cooldown doubles with every cooldown in a row, up to maxCooldown
+func (p *Pool) cooldown(account *Account, base time.Duration, now time.Time) {
+	d := base << account.Health.Cooldowns
+	if d > maxCooldown || d <= 0 {
+		d = maxCooldown
+	}
+	account.Health.Cooldowns++
+	account.Health.CooldownUntil = now.Add(d)
+	logger.Error("Instagram account on cooldown", zap.String("login", account.Login), zap.Duration("for", d))
+}
+
+func (p *Pool) healthy(account *Account) bool {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return account.healthy(time.Now())
+}
+
+// Restore puts a parked or cooled down account back into rotation
+func (p *Pool) Restore(login string) {
+	p.mu.Lock()
+	account := p.find(login)
+	if account == nil {
+		p.mu.Unlock()
+		return
+	}
+	account.Health = Health{LastUsed: account.Health.LastUsed}
+	value, _ := json.Marshal(account.Health)
+	p.mu.Unlock()
+
+	if err := redis.StoreAccountHealth(context.Background(), login, string(value)); err != nil {
+		logger.Error("Error storing account health", zap.String("login", login), zap.Error(err))
+	}
+}
+
+// StartLogin logs in with login again, out of rotation. On a challenge or 2FA
+// request the login is parked until Verify is called with the code.
+func (p *Pool) StartLogin(ctx context.Context, login string) error {
+	p.mu.Lock()
+	account := p.find(login)
+	p.mu.Unlock()
+	if account == nil {
+		return fmt.Errorf("unknown Instagram account: %s", login)
+	}
+	forget(login)
+	InvalidateSession(ctx, login)
+	if _, err := Login(ctx, account.Login, account.Password); err != nil {
+		return err
+	}
+	p.Restore(login)
+	return nil
+}
+
+// Verify finishes a parked login and puts the account back into rotation
+func (p *Pool) Verify(ctx context.Context, login string, code string) error {
+	if err := Verify(ctx, login, code); err != nil {
+		return err
+	}
+	p.Restore(login)
+	return nil
+}
+
+func (p *Pool) find(login string) *Account {
+	for _, account := range p.accounts {
+		if account.Login == login {
+			return account
+		}
+	}
+	return nil
+}
+
+// Accounts returns a copy of every account with its health
+func (p *Pool) Accounts() []Account {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	accounts := make([]Account, 0, len(p.accounts))
+	for _, account := range p.accounts {
+		accounts = append(accounts, *account)
+	}
+	return accounts
+}
+
+// Session is a logged in account that is rotated every config.AccountRotateEvery
+// profiles, a profile is counted when its outcome is reported
+type Session struct {
+	pool     *Pool
+	account  *Account
+	insta    *goinsta.Instagram
+	profiles int
+}
+
+func (p *Pool) Session() *Session {
+	return &Session{pool: p}
+}
+
+// Instagram returns a client for the next profile visit, logging in with another
+// account when the current one served enough profiles or is not healthy anymore.
+func (s *Session) Instagram(ctx context.Context) (*goinsta.Instagram, error) {
+	every := config.Config.AccountRotateEvery
+	if s.insta != nil && s.pool.healthy(s.account) && (every <= 0 || s.profiles < every) {
+		return s.insta, nil
+	}
+
+	var lastErr error
+	for attempt := 0; attempt < len(s.pool.accounts); attempt++ {
+		account, err := s.pool.Acquire()
+		if err != nil {
+			if lastErr != nil {
+				return nil, fmt.Errorf("%w: %v", err, lastErr)
+			}
+			return nil, err
+		}
+		insta, err := Login(ctx, account.Login, account.Password)
+		if err != nil {
+			if ctx.Err() != nil {
+				return nil, ctx.Err()
+			}
+			s.pool.Report(account, err)
+			lastErr = err
+			continue
+		}
+		s.account = account
+		s.insta = insta
+		s.profiles = 0
+		return insta, nil
+	}
+	return nil, fmt.Errorf("%w: %v", ErrNoAccounts, lastErr)
+}
+
+// Report records the outcome of a profile visited with the session client. A
+// rejected session is invalidated and the next call to Instagram logs in again.
+func (s *Session) Report(err error) {
+	if s.account == nil {
+		return
+	}
+	s.profiles++
+	if IsSessionRejected(err) {
+		InvalidateSession(context.Background(), s.account.Login)
+		s.insta = nil
+		return
+	}
+	s.pool.Report(s.account, err)
+}
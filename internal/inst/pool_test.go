@@ -0,0 +1,37 @@
+package inst
+
+import (
+	"errors"
+	"fmt"
+	"github.com/Davincible/goinsta"
+	"net"
+	"net/url"
+	"testing"
+)
+
+func TestErrorClasses(t *testing.T) {
+	tests := []struct {
+		name    string
+		err     error
+		profile bool
+		failure bool
+	}{
+		{"unknown username", fmt.Errorf("failed to visit profile x: %w", errors.New("Profile not found")), true, false},
+		{"not in search", goinsta.ErrSearchUserNotFound, true, false},
+		{"deleted user", goinsta.ErrorN{Status: "404", Message: "Not Found"}, true, false},
+		{"private user", fmt.Errorf("failed to fetch stories of x: %w", goinsta.Error400{Message: "Not authorized to view user"}), true, false},
+		{"login", fmt.Errorf("%w: %w", ErrLoginFailed, goinsta.ErrBadPassword), false, true},
+		{"transport", &url.Error{Op: "Get", URL: "https://i.instagram.com", Err: &net.OpError{Op: "dial", Err: errors.New("connection refused")}}, false, true},
+		{"server error", goinsta.ErrorN{Status: "500"}, false, true},
+		{"unavailable", goinsta.Error503{Message: "Instagram API error. Try it later."}, false, true},
+		{"other refusal", goinsta.Error400{Message: "feedback_required"}, false, false},
+	}
+	for _, tt := range tests {
+		if got := isProfileError(tt.err); got != tt.profile {
+			t.Errorf("%s: isProfileError = %v, want %v", tt.name, got, tt.profile)
+		}
+		if got := isAccountFailure(tt.err); got != tt.failure {
+			t.Errorf("%s: isAccountFailure = %v, want %v", tt.name, got, tt.failure)
+		}
+	}
+}
@@ -0,0 +1,248 @@
+package jobs
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/internal/delivery"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"time"
+)
+
+type Status string
+
+const (
+	Queued    Status = "queued"
+	Running   Status = "running"
+	Done      Status = "done"
+	Failed    Status = "failed"
+	Cancelled Status = "cancelled"
+)
+
+// Jobs are kept for a week after the last update
+const ttl = 7 * 24 * time.Hour
+
+// Result is the outcome of a single username of a job. Used counts units paid
+// to providers, Cached the stories served from the cache for free and Refunded
+// the units given back after failed provider calls. SkippedStories were
+// summarized but left out as not interesting. Fingerprint identifies the
+// fetched stories, it is empty when the profile could not be fetched.
+type Result struct {
+	Username       string  `json:"username"`
+	Summary        string  `json:"summary"`
+	Skipped        bool    `json:"skipped"`
+	SkippedStories int     `json:"skippedStories"`
+	Used           float32 `json:"used"`
+	Cached         int     `json:"cached"`
+	Refunded       float32 `json:"refunded"`
+	Stories        []Story `json:"stories"`
+	Fingerprint    string  `json:"fingerprint,omitempty"`
+}
+
+// Story is a summarized story of a result
+type Story struct {
+	ID        string    `json:"id"`
+	Type      string    `json:"type"`
+	Link      string    `json:"link"`
+	Thumbnail string    `json:"thumbnail"`
+	TakenAt   time.Time `json:"takenAt"`
+	Summary   string    `json:"summary"`
+	Tags      []string  `json:"tags,omitempty"`
+}
+
+// Job is a summarize request of usernames. Fingerprint identifies the stories
+// the job fetched, a daily job whose Fingerprint equals Previous, the one of
+// the last digest, stops as Unchanged before summarizing anything.
+type Job struct {
+	ID          string            `json:"id"`
+	UserID      string            `json:"userId"`
+	Usernames   []string          `json:"usernames"`
+	Left        float32           `json:"left"`
+	IsDaily     bool              `json:"isDaily"`
+	Preferences string            `json:"preferences"`
+	Format      string            `json:"format"`
+	Status      Status            `json:"status"`
+	Results     []Result          `json:"results"`
+	Medias      []shotstack.Asset `json:"medias"`
+	Used        float32           `json:"used"`
+	Result      string            `json:"result"`
+	LinkToVideo string            `json:"linkToVideo"`
+	Error       string            `json:"error"`
+	Deliveries  []delivery.Status `json:"deliveries"`
+	Fingerprint string            `json:"fingerprint,omitempty"`
+	Previous    string            `json:"previous,omitempty"`
+	Unchanged   bool              `json:"unchanged,omitempty"`
+	CreatedAt   time.Time         `json:"createdAt"`
+	UpdatedAt   time.Time         `json:"updatedAt"`
+}
+
+func New(id string, userID string, usernames []string, left float32, isDaily bool, preferences string) *Job {
+	now := time.Now()
+	return &Job{
+		ID:          id,
+		UserID:      userID,
+		Usernames:   usernames,
+		Left:        left,
+		IsDaily:     isDaily,
+		Preferences: preferences,
+		Status:      Queued,
+		Results:     []Result{},
+		Medias:      []shotstack.Asset{},
+		CreatedAt:   now,
+		UpdatedAt:   now,
+	}
+}
+
+// Finished reports whether the job reached a final state and will not run again by itself
+func (j *Job) Finished() bool {
+	return j.Status == Done || j.Status == Failed || j.Status == Cancelled
+}
+
+// StoriesFingerprint combines the fingerprints of the stories of every fetched
+// username, it is empty when no username could be fetched
+func (j *Job) StoriesFingerprint() string {
+	return Fingerprint(j.Results)
+}
+
+// Fingerprint combines the fingerprints of results, it is empty when none of
+// them was fetched
+func Fingerprint(results []Result) string {
+	hash := sha256.New()
+	fetched := false
+	for _, result := range results {
+		if result.Fingerprint == "" {
+			continue
+		}
+		fetched = true
+		hash.Write([]byte(result.Username + ":" + result.Fingerprint + "\n"))
+	}
+	if !fetched {
+		return ""
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// Remaining returns usernames that have no result yet, in request order
+func (j *Job) Remaining() []string {
+	processed := make(map[string]bool, len(j.Results))
+	for _, result := range j.Results {
+		processed[result.Username] = true
+	}
+	remaining := make([]string, 0, len(j.Usernames))
+	for _, username := range j.Usernames {
+		if !processed[username] {
+			remaining = append(remaining, username)
+		}
+	}
+	return remaining
+}
+
+func Save(ctx context.Context, job *Job) error {
+	job.UpdatedAt = time.Now()
+	value, err := json.Marshal(job)
+	if err != nil {
+		return fmt.Errorf("failed to marshal job %s: %v", job.ID, err)
+	}
+	return redis.StoreJob(ctx, job.ID, string(value), !job.Finished(), ttl)
+}
+
+func Get(ctx context.Context, id string) (*Job, error) {
+	value, err := redis.GetJob(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var job Job
+	if err = json.Unmarshal([]byte(value), &job); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal job %s: %v", id, err)
+	}
+	return &job, nil
+}
+
+// Active returns jobs that were queued or running when they were saved last time
+func Active(ctx context.Context) ([]*Job, error) {
+	ids, err := redis.GetActiveJobs(ctx)
+	if err != nil {
+		return nil, err
+	}
+	active := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := Get(ctx, id)
+		if err != nil {
+			redis.RemoveActiveJob(ctx, id)
+			continue
+		}
+		active = append(active, job)
+	}
+	return active, nil
+}
+
+// userJobs is how many finished jobs are listed per user
+const userJobs = 50
+
+// AddToUser lists a finished job among the jobs of its user
+func AddToUser(ctx context.Context, job *Job) error {
+	return redis.PushUserJob(ctx, job.UserID, job.ID, userJobs)
+}
+
+// OfUser returns the newest finished jobs of a user that are still kept, newest first
+func OfUser(ctx context.Context, userID string, count int64) ([]*Job, error) {
+	ids, err := redis.GetUserJobs(ctx, userID, count)
+	if err != nil {
+		return nil, err
+	}
+	result := make([]*Job, 0, len(ids))
+	for _, id := range ids {
+		job, err := Get(ctx, id)
+		if err != nil {
+			continue
+		}
+		result = append(result, job)
+	}
+	return result, nil
+}
+
+// Statuses of a daily digest
+const (
+	DailyRunning   = "running"
+	DailyRetrying  = "retrying"
+	DailyDone      = "done"
+	DailyUnchanged = "unchanged"
+	DailyFailed    = "failed"
+)
+
+// Daily is the state of the daily digest of a user for Date, a date in the
+// time zone of the user. Fingerprint is of the stories the last done digest
+// was made of.
+type Daily struct {
+	UserID      string    `json:"userId"`
+	Date        string    `json:"date"`
+	Status      string    `json:"status"`
+	JobID       string    `json:"jobId"`
+	Attempts    int       `json:"attempts"`
+	NextAttempt time.Time `json:"nextAttempt"`
+	Error       string    `json:"error"`
+	Fingerprint string    `json:"fingerprint"`
+}
+
+func SaveDaily(ctx context.Context, daily *Daily) error {
+	value, err := json.Marshal(daily)
+	if err != nil {
+		return fmt.Errorf("failed to marshal daily digest of user %s: %v", daily.UserID, err)
+	}
+	return redis.StoreDaily(ctx, daily.UserID, string(value))
+}
+
+func GetDaily(ctx context.Context, userID string) (*Daily, error) {
+	value, err := redis.GetDaily(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	var daily Daily
+	if err = json.Unmarshal([]byte(value), &daily); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal daily digest of user %s: %v", userID, err)
+	}
+	return &daily, nil
+}
@@ -0,0 +1,207 @@
+package prompts
+
+import (
+	"bytes"
+	"embed"
+	"errors"
+	"fmt"
+	"io/fs"
+	"os"
+	"regexp"
+	"strings"
+	"sync"
+	"text/template"
+)
+
+// Prompts are text/template files named <prompt>.<variant>.tmpl. The first
+// line of a file declares its version: {{/* version: 2 */ -}}
+//
+//go:embed templates/*.tmpl
+var defaults embed.FS
+
+const (
+	StoryPrompt = "story"
+	MergePrompt = "merge"
+)
+
+const (
+	Personal = "personal"
+	Business = "business"
+)
+
+var ErrNotFound = errors.New("prompt not found")
+
+// Story are the variables of story prompts
+type Story struct {
+	Username string
+	// Media is video or image
+	Media     string
+	Previous  string
+	History   string
+	Events    []interface{}
+	Hashtags  []interface{}
+	Polls     []interface{}
+	Locations []interface{}
+	Questions []interface{}
+	Sliders   []interface{}
+	Mentions  []string
+}
+
+// Merge are the variables of merge prompts
+type Merge struct {
+	Preferences string
+}
+
+// Every prompt has to exist for both variants and render with these
+var samples = map[string]interface{}{
+	StoryPrompt: Story{Username: "username", Media: "image", Previous: "[]", History: "-"},
+	MergePrompt: Merge{Preferences: "-"},
+}
+
+// Story prompts have to ask for the fields summarizers decode
+var responseFields = map[string][]string{
+	StoryPrompt: {`"description"`, `"interesting"`, `"relevance"`, `"addIt"`, `"clip_length"`},
+}
+
+var versionLine = regexp.MustCompile(`^\{\{-?\s*/\*\s*version:\s*(\S+)\s*\*/\s*-?\}\}`)
+
+type prompt struct {
+	version  string
+	template *template.Template
+}
+
+var (
+	mu       sync.RWMutex
+	registry map[string]prompt
+)
+
+func init() {
+	loaded, err := load("")
+	if err != nil {
+		panic(fmt.Sprintf("embedded prompts: %v", err))
+	}
+	registry = loaded
+}
+
+// Load reads prompts from dir over the embedded ones and validates them. The
+// prompts in use are replaced only when all of them are valid.
+func Load(dir string) error {
+	loaded, err := load(dir)
+	if err != nil {
+		return err
+	}
+	mu.Lock()
+	registry = loaded
+	mu.Unlock()
+	return nil
+}
+
+func load(dir string) (map[string]prompt, error) {
+	loaded := make(map[string]prompt)
+	embedded, err := fs.Sub(defaults, "templates")
+	if err != nil {
+		return nil, err
+	}
+	sources := []fs.FS{embedded}
+	if dir != "" {
+		if _, err = os.Stat(dir); err != nil {
+			return nil, fmt.Errorf("failed to read prompts: %w", err)
+		}
+		sources = append(sources, os.DirFS(dir))
+	}
+	for _, source := range sources {
+		files, err := fs.Glob(source, "*.tmpl")
+		if err != nil {
+			return nil, err
+		}
+		for _, file := range files {
+			content, err := fs.ReadFile(source, file)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read prompt %s: %w", file, err)
+			}
+			name := strings.TrimSuffix(file, ".tmpl")
+			p, err := parse(name, string(content))
+			if err != nil {
+				return nil, err
+			}
+			loaded[name] = p
+		}
+	}
+	if err := validate(loaded); err != nil {
+		return nil, err
+	}
+	return loaded, nil
+}
+
+func parse(name string, content string) (prompt, error) {
+	match := versionLine.FindStringSubmatch(content)
+	if match == nil {
+		return prompt{}, fmt.Errorf("prompt %s: missing version line", name)
+	}
+	t, err := template.New(name).Option("missingkey=error").Parse(content)
+	if err != nil {
+		return prompt{}, fmt.Errorf("prompt %s: %w", name, err)
+	}
+	return prompt{version: name + "@" + match[1], template: t}, nil
+}
+
+func validate(loaded map[string]prompt) error {
+	for name, sample := range samples {
+		for _, variant := range []string{Personal, Business} {
+			key := name + "." + variant
+			p, ok := loaded[key]
+			if !ok {
+				return fmt.Errorf("prompt %s: %w", key, ErrNotFound)
+			}
+			text, err := execute(p, sample)
+			if err != nil {
+				return err
+			}
+			for _, field := range responseFields[name] {
+				if !strings.Contains(text, field) {
+					return fmt.Errorf("prompt %s: response format misses %s", key, field)
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func execute(p prompt, data interface{}) (string, error) {
+	var buf bytes.Buffer
+	if err := p.template.Execute(&buf, data); err != nil {
+		return "", fmt.Errorf("prompt %s: %w", p.version, err)
+	}
+	return strings.TrimSpace(buf.String()), nil
+}
+
+// Render executes the personal or business variant of prompt name with data.
+// It returns the prompt and its version.
+func Render(name string, business bool, data interface{}) (string, string, error) {
+	key := name + "." + Personal
+	if business {
+		key = name + "." + Business
+	}
+	mu.RLock()
+	p, ok := registry[key]
+	mu.RUnlock()
+	if !ok {
+		return "", "", fmt.Errorf("prompt %s: %w", key, ErrNotFound)
+	}
+	text, err := execute(p, data)
+	if err != nil {
+		return "", "", err
+	}
+	return text, p.version, nil
+}
+
+// Versions returns the version of every loaded prompt
+func Versions() map[string]string {
+	mu.RLock()
+	defer mu.RUnlock()
+	versions := make(map[string]string, len(registry))
+	for name, p := range registry {
+		versions[name] = p.version
+	}
+	return versions
+}
@@ -3,39 +3,38 @@ package redis
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/go-redis/redis/v8"
 	"github.com/rendizi/stay-connected-inst/config"
-	"github.com/rendizi/stay-connected-inst/pkg/logger"
-	"log"
 	"time"
 )
 
 var historyClient *redis.Client
 var cookiesClient *redis.Client
 
+// Clients connect on their first command, Ping checks both servers are reachable
 func init() {
 	historyClient = redis.NewClient(&redis.Options{
 		Addr:     config.Config.RedisHistoryAddress,
 		Password: config.Config.RedisHistoryPassword,
 		DB:       0,
 	})
-	_, err := historyClient.Ping(context.Background()).Result()
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to connect to Redis: %v", err))
-		log.Fatal()
-	}
-
 	cookiesClient = redis.NewClient(&redis.Options{
 		Addr:     config.Config.RedisCookiesAddress,
 		Password: config.Config.RedisCookiesPassword,
 		DB:       0,
 	})
-	_, err = cookiesClient.Ping(context.Background()).Result()
-	if err != nil {
-		logger.Error(fmt.Sprintf("failed to connect to Redis: %v", err))
-		log.Fatal()
+}
+
+func Ping(ctx context.Context) error {
+	if err := historyClient.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %v", err)
+	}
+	if err := cookiesClient.Ping(ctx).Err(); err != nil {
+		return fmt.Errorf("failed to connect to Redis: %v", err)
 	}
+	return nil
 }
 
 func GetCookies(ctx context.Context, username string) (string, error) {
@@ -57,6 +56,14 @@ func StoreCookies(ctx context.Context, username string, cookies string) error {
 	return nil
 }
 
+func DeleteCookies(ctx context.Context, username string) error {
+	err := cookiesClient.Del(ctx, username).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete cookies from Redis: %v", err)
+	}
+	return nil
+}
+
 func StoreSummarizes(ctx context.Context, key string, value map[string]interface{}, stringified string, duration time.Duration) error {
 	var err error
 	var result string
@@ -77,17 +84,300 @@ func StoreSummarizes(ctx context.Context, key string, value map[string]interface
 	return nil
 }
 
-func GetSummarizes(ctx context.Context, key string) (string, bool, error) {
+// GetSummary returns the raw value of a cached summary
+func GetSummary(ctx context.Context, key string) (string, error) {
 	value, err := historyClient.Get(ctx, key).Result()
 	if err == redis.Nil {
-		return "", false, fmt.Errorf("key %s does not exist", key)
+		return "", fmt.Errorf("key %s: %w", key, ErrNotExist)
 	} else if err != nil {
-		return "", false, fmt.Errorf("failed to get key %s from Redis: %v", key, err)
+		return "", fmt.Errorf("failed to get key %s from Redis: %v", key, err)
 	}
-	var data map[string]interface{}
-	err = json.Unmarshal([]byte(value), &data)
+	return value, nil
+}
+
+var ErrNotExist = errors.New("key does not exist")
+
+const activeJobsKey = "jobs:active"
+
+func jobKey(id string) string {
+	return "job:" + id
+}
+
+// StoreJob saves a job and keeps the set of active jobs in sync with it
+func StoreJob(ctx context.Context, id string, value string, active bool, duration time.Duration) error {
+	pipe := historyClient.TxPipeline()
+	pipe.Set(ctx, jobKey(id), value, duration)
+	if active {
+		pipe.SAdd(ctx, activeJobsKey, id)
+	} else {
+		pipe.SRem(ctx, activeJobsKey, id)
+	}
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to store job %s in Redis: %v", id, err)
+	}
+	return nil
+}
+
+func GetJob(ctx context.Context, id string) (string, error) {
+	value, err := historyClient.Get(ctx, jobKey(id)).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("job %s: %w", id, ErrNotExist)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get job %s from Redis: %v", id, err)
+	}
+	return value, nil
+}
+
+func GetActiveJobs(ctx context.Context) ([]string, error) {
+	ids, err := historyClient.SMembers(ctx, activeJobsKey).Result()
 	if err != nil {
-		return value, false, nil
+		return nil, fmt.Errorf("failed to get active jobs from Redis: %v", err)
 	}
-	return data["value"].(string), data["addIt"].(bool), nil
+	return ids, nil
+}
+
+func RemoveActiveJob(ctx context.Context, id string) error {
+	err := historyClient.SRem(ctx, activeJobsKey, id).Err()
+	if err != nil {
+		return fmt.Errorf("failed to remove active job %s from Redis: %v", id, err)
+	}
+	return nil
+}
+
+const accountsKey = "inst:accounts"
+
+func accountHealthKey(login string) string {
+	return "inst:health:" + login
+}
+
+// GetAccounts returns Instagram accounts added to the pool at runtime, login to password
+func GetAccounts(ctx context.Context) (map[string]string, error) {
+	accounts, err := cookiesClient.HGetAll(ctx, accountsKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get accounts from Redis: %v", err)
+	}
+	return accounts, nil
+}
+
+func StoreAccountHealth(ctx context.Context, login string, health string) error {
+	err := cookiesClient.Set(ctx, accountHealthKey(login), health, 0).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store health of account %s in Redis: %v", login, err)
+	}
+	return nil
+}
+
+func GetAccountHealth(ctx context.Context, login string) (string, error) {
+	health, err := cookiesClient.Get(ctx, accountHealthKey(login)).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("health of account %s: %w", login, ErrNotExist)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get health of account %s from Redis: %v", login, err)
+	}
+	return health, nil
+}
+
+func IncrCounter(ctx context.Context, name string) error {
+	err := historyClient.Incr(ctx, "counter:"+name).Err()
+	if err != nil {
+		return fmt.Errorf("failed to increment counter %s in Redis: %v", name, err)
+	}
+	return nil
+}
+
+func GetCounter(ctx context.Context, name string) (int64, error) {
+	value, err := historyClient.Get(ctx, "counter:"+name).Int64()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get counter %s from Redis: %v", name, err)
+	}
+	return value, nil
+}
+
+var ErrQuotaExceeded = errors.New("quota exceeded")
+
+func userKey(id string) string {
+	return "user:" + id
+}
+
+func usageKey(id string, period string) string {
+	return "user:" + id + ":usage:" + period
+}
+
+func ledgerKey(id string) string {
+	return "user:" + id + ":ledger"
+}
+
+const usersKey = "users"
+
+// StoreUser saves a user and adds it to the set of all users
+func StoreUser(ctx context.Context, id string, value string) error {
+	pipe := historyClient.TxPipeline()
+	pipe.Set(ctx, userKey(id), value, 0)
+	pipe.SAdd(ctx, usersKey, id)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to store user %s in Redis: %v", id, err)
+	}
+	return nil
+}
+
+func GetUserIDs(ctx context.Context) ([]string, error) {
+	ids, err := historyClient.SMembers(ctx, usersKey).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get users from Redis: %v", err)
+	}
+	return ids, nil
+}
+
+func GetUser(ctx context.Context, id string) (string, error) {
+	value, err := historyClient.Get(ctx, userKey(id)).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("user %s: %w", id, ErrNotExist)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get user %s from Redis: %v", id, err)
+	}
+	return value, nil
+}
+
+// chargeScript adds ARGV[1] units to the usage counter unless a positive charge
+// would go over the limit ARGV[2], and appends the entry ARGV[4] to the ledger
+var chargeScript = redis.NewScript(`
+local used = tonumber(redis.call('GET', KEYS[1]) or '0')
+local units = tonumber(ARGV[1])
+if units > 0 and used + units > tonumber(ARGV[2]) then
+	return {0, tostring(used)}
+end
+used = redis.call('INCRBYFLOAT', KEYS[1], ARGV[1])
+redis.call('EXPIRE', KEYS[1], ARGV[3])
+redis.call('RPUSH', KEYS[2], ARGV[4])
+redis.call('LTRIM', KEYS[2], -tonumber(ARGV[5]), -1)
+return {1, used}
+`)
+
+// ChargeUsage atomically adds units to the usage of user id in period and
+// records entry in the ledger, keeping its last size entries. Negative units
+// are refunds and always succeed. Returns the usage after the charge.
+func ChargeUsage(ctx context.Context, id string, period string, units float64, limit float64, entry string, duration time.Duration, size int) (float64, error) {
+	keys := []string{usageKey(id, period), ledgerKey(id)}
+	result, err := chargeScript.Run(ctx, historyClient, keys, units, limit, int(duration.Seconds()), entry, size).Slice()
+	if err != nil {
+		return 0, fmt.Errorf("failed to charge usage of user %s in Redis: %v", id, err)
+	}
+	var used float64
+	fmt.Sscan(fmt.Sprint(result[1]), &used)
+	if ok, _ := result[0].(int64); ok == 0 {
+		return used, fmt.Errorf("user %s used %v of %v: %w", id, used, limit, ErrQuotaExceeded)
+	}
+	return used, nil
+}
+
+func GetUsage(ctx context.Context, id string, period string) (float64, error) {
+	used, err := historyClient.Get(ctx, usageKey(id, period)).Float64()
+	if err == redis.Nil {
+		return 0, nil
+	} else if err != nil {
+		return 0, fmt.Errorf("failed to get usage of user %s from Redis: %v", id, err)
+	}
+	return used, nil
+}
+
+// GetLedger returns the last count ledger entries of user id, oldest first
+func GetLedger(ctx context.Context, id string, count int64) ([]string, error) {
+	entries, err := historyClient.LRange(ctx, ledgerKey(id), -count, -1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get ledger of user %s from Redis: %v", id, err)
+	}
+	return entries, nil
+}
+
+func dailyKey(userID string) string {
+	return "daily:" + userID
+}
+
+func StoreDaily(ctx context.Context, userID string, value string) error {
+	err := historyClient.Set(ctx, dailyKey(userID), value, 0).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store daily digest of user %s in Redis: %v", userID, err)
+	}
+	return nil
+}
+
+func GetDaily(ctx context.Context, userID string) (string, error) {
+	value, err := historyClient.Get(ctx, dailyKey(userID)).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("daily digest of user %s: %w", userID, ErrNotExist)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get daily digest of user %s from Redis: %v", userID, err)
+	}
+	return value, nil
+}
+
+func userJobsKey(id string) string {
+	return "user:" + id + ":jobs"
+}
+
+// PushUserJob records a finished job of user id, keeping the newest size jobs
+func PushUserJob(ctx context.Context, id string, jobID string, size int64) error {
+	pipe := historyClient.TxPipeline()
+	pipe.LPush(ctx, userJobsKey(id), jobID)
+	pipe.LTrim(ctx, userJobsKey(id), 0, size-1)
+	_, err := pipe.Exec(ctx)
+	if err != nil {
+		return fmt.Errorf("failed to store job of user %s in Redis: %v", id, err)
+	}
+	return nil
+}
+
+// GetUserJobs returns IDs of the newest count finished jobs of user id, newest first
+func GetUserJobs(ctx context.Context, id string, count int64) ([]string, error) {
+	ids, err := historyClient.LRange(ctx, userJobsKey(id), 0, count-1).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get jobs of user %s from Redis: %v", id, err)
+	}
+	return ids, nil
+}
+
+func historyKey(username string) string {
+	return "history:" + username
+}
+
+func StoreHistory(ctx context.Context, username string, value string, duration time.Duration) error {
+	err := historyClient.Set(ctx, historyKey(username), value, duration).Err()
+	if err != nil {
+		return fmt.Errorf("failed to store history of %s in Redis: %v", username, err)
+	}
+	return nil
+}
+
+func GetHistory(ctx context.Context, username string) (string, error) {
+	value, err := historyClient.Get(ctx, historyKey(username)).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("history of %s: %w", username, ErrNotExist)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get history of %s from Redis: %v", username, err)
+	}
+	return value, nil
+}
+
+// GetLegacyHistory returns history stored under the bare username, a JSON list
+// of summaries with the date appended
+func GetLegacyHistory(ctx context.Context, username string) (string, error) {
+	value, err := historyClient.Get(ctx, username).Result()
+	if err == redis.Nil {
+		return "", fmt.Errorf("legacy history of %s: %w", username, ErrNotExist)
+	} else if err != nil {
+		return "", fmt.Errorf("failed to get legacy history of %s from Redis: %v", username, err)
+	}
+	return value, nil
+}
+
+func DeleteLegacyHistory(ctx context.Context, username string) error {
+	err := historyClient.Del(ctx, username).Err()
+	if err != nil {
+		return fmt.Errorf("failed to delete legacy history of %s from Redis: %v", username, err)
+	}
+	return nil
 }
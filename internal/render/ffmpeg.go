@@ -0,0 +1,247 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"os/exec"
+	"path"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+var ErrFFmpeg = errors.New("ffmpeg failed")
+
+const (
+	width  = 720
+	height = 1280
+	fps    = 30
+	// Clips overlap by this many seconds, like on the Shotstack timeline
+	transition = 1.0
+	fade       = 0.5
+	// Slide effects move the clip by this part of the frame
+	slide = 0.1
+)
+
+// FFmpeg renders the Shotstack timeline of the medias locally: every clip gets
+// its effect in a segment of its own, segments are then crossfaded into one
+// video with the soundtrack. Soundtrack overrides the one of the timeline,
+// "none" leaves the video silent.
+type FFmpeg struct {
+	Binary     string
+	Soundtrack string
+	Storage    Storage
+}
+
+func (f FFmpeg) Render(ctx context.Context, medias []shotstack.Asset) (string, error) {
+	data, err := shotstack.GenerateVideoJson(medias)
+	if err != nil {
+		return "", err
+	}
+	var clips []shotstack.Clip
+	for _, track := range data.Timeline.Tracks {
+		clips = append(clips, track.Clips...)
+	}
+	if len(clips) == 0 {
+		return "", ErrNothingToRender
+	}
+
+	dir, err := os.MkdirTemp("", "render-")
+	if err != nil {
+		return "", fmt.Errorf("failed to create render directory: %w", err)
+	}
+	defer os.RemoveAll(dir)
+
+	segments := make([]string, len(clips))
+	for i, clip := range clips {
+		src, err := fetch(ctx, clip.Asset.Src, dir, fmt.Sprintf("asset-%d", i))
+		if err != nil {
+			return "", err
+		}
+		segments[i] = filepath.Join(dir, fmt.Sprintf("segment-%d.mp4", i))
+		if err = f.run(ctx, segmentArgs(clip, src, segments[i], i == 0, i == len(clips)-1)); err != nil {
+			return "", err
+		}
+	}
+
+	soundtrack := f.Soundtrack
+	if soundtrack == "" {
+		soundtrack = data.Timeline.Soundtrack.Src
+	}
+	var audio string
+	if soundtrack != "" && soundtrack != "none" {
+		// The video is still worth having without music
+		if audio, err = fetch(ctx, soundtrack, dir, "soundtrack"); err != nil {
+			if ctx.Err() != nil {
+				return "", ctx.Err()
+			}
+			logger.Error("Error fetching soundtrack", zap.String("src", soundtrack), zap.Error(err))
+		}
+	}
+
+	out := filepath.Join(dir, "recap.mp4")
+	if err = f.run(ctx, joinArgs(clips, segments, audio, out)); err != nil {
+		return "", err
+	}
+	return f.Storage.Save(ctx, uuid.NewString()+".mp4", out)
+}
+
+func (f FFmpeg) run(ctx context.Context, args []string) error {
+	cmd := exec.CommandContext(ctx, f.Binary, append([]string{"-hide_banner", "-loglevel", "error", "-y"}, args...)...)
+	output, err := cmd.CombinedOutput()
+	if err != nil {
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		return fmt.Errorf("%w: %v: %s", ErrFFmpeg, err, strings.TrimSpace(string(output)))
+	}
+	return nil
+}
+
+// segmentArgs renders one clip filling the frame with its effect. Only the
+// first clip fades in and the last one fades out, the others are crossfaded.
+func segmentArgs(clip shotstack.Clip, src string, out string, first bool, last bool) []string {
+	length := float64(clip.Length)
+	var args []string
+	if clip.Asset.Type == "image" {
+		args = append(args, "-loop", "1")
+	}
+	args = append(args, "-t", seconds(length), "-i", src)
+
+	filters := []string{
+		fmt.Sprintf("scale=%d:%d:force_original_aspect_ratio=increase,crop=%d:%d,setsar=1,fps=%d", width, height, width, height, fps),
+		// Videos shorter than their clip keep their last frame
+		"tpad=stop_mode=clone:stop_duration=" + seconds(length),
+	}
+	if effect := effectFilter(clip.Effect, length); effect != "" {
+		filters = append(filters, effect)
+	}
+	if first && clip.Transition.In == "fade" {
+		filters = append(filters, "fade=t=in:st=0:d="+seconds(fade))
+	}
+	if last && clip.Transition.Out == "fade" {
+		filters = append(filters, "fade=t=out:st="+seconds(length-fade)+":d="+seconds(fade))
+	}
+	return append(args,
+		"-vf", strings.Join(filters, ","),
+		"-an", "-t", seconds(length), "-r", strconv.Itoa(fps),
+		"-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p",
+		out,
+	)
+}
+
+// effectFilter is the ffmpeg filter of a Shotstack effect, zooms scale the
+// frame by 10% over the clip and slides move it by 10% of its size
+func effectFilter(effect string, length float64) string {
+	frames := strconv.Itoa(int(length * fps))
+	dx, dy := int(width*slide), int(height*slide)
+	crop := func(x string, y string) string {
+		return fmt.Sprintf("scale=%d:%d,crop=%d:%d:x='%s':y='%s'", width+dx, height+dy, width, height, x, y)
+	}
+	progress := "t/" + seconds(length)
+	switch effect {
+	case "zoomIn":
+		return fmt.Sprintf("zoompan=z='1+%v*on/%s':d=1:x='iw/2-iw/zoom/2':y='ih/2-ih/zoom/2':s=%dx%d:fps=%d", slide, frames, width, height, fps)
+	case "zoomOut":
+		return fmt.Sprintf("zoompan=z='%v-%v*on/%s':d=1:x='iw/2-iw/zoom/2':y='ih/2-ih/zoom/2':s=%dx%d:fps=%d", 1+slide, slide, frames, width, height, fps)
+	case "slideLeft":
+		return crop(fmt.Sprintf("%d*%s", dx, progress), strconv.Itoa(dy/2))
+	case "slideRight":
+		return crop(fmt.Sprintf("%d-%d*%s", dx, dx, progress), strconv.Itoa(dy/2))
+	case "slideUp":
+		return crop(strconv.Itoa(dx/2), fmt.Sprintf("%d*%s", dy, progress))
+	case "slideDown":
+		return crop(strconv.Itoa(dx/2), fmt.Sprintf("%d-%d*%s", dy, dy, progress))
+	}
+	return ""
+}
+
+// joinArgs crossfades the segments into one video and lays the soundtrack
+// under it, fading the music in and out
+func joinArgs(clips []shotstack.Clip, segments []string, audio string, out string) []string {
+	var args []string
+	for _, segment := range segments {
+		args = append(args, "-i", segment)
+	}
+	if audio != "" {
+		args = append(args, "-stream_loop", "-1", "-i", audio)
+	}
+
+	var filters []string
+	video := "0:v"
+	total := float64(clips[0].Length)
+	for i := 1; i < len(clips); i++ {
+		length := float64(clips[i].Length)
+		d := min(transition, float64(clips[i-1].Length)/2, length/2)
+		label := fmt.Sprintf("v%d", i)
+		filters = append(filters, fmt.Sprintf("[%s][%d:v]xfade=transition=fade:duration=%s:offset=%s[%s]", video, i, seconds(d), seconds(total-d), label))
+		video = label
+		total += length - d
+	}
+	if audio != "" {
+		filters = append(filters, fmt.Sprintf("[%d:a]atrim=0:%s,afade=t=in:st=0:d=1,afade=t=out:st=%s:d=1[a]", len(segments), seconds(total), seconds(max(total-1, 0))))
+	}
+	if len(filters) > 0 {
+		args = append(args, "-filter_complex", strings.Join(filters, ";"))
+	}
+	if len(clips) > 1 {
+		video = "[" + video + "]"
+	}
+	args = append(args, "-map", video)
+	if audio != "" {
+		args = append(args, "-map", "[a]", "-c:a", "aac")
+	}
+	return append(args,
+		"-t", seconds(total),
+		"-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p", "-movflags", "+faststart",
+		out,
+	)
+}
+
+func seconds(s float64) string {
+	return strconv.FormatFloat(s, 'f', 3, 64)
+}
+
+// fetch downloads src into dir. Only http and https assets are fetched, rendered
+// videos are public and must never contain files of the server.
+func fetch(ctx context.Context, src string, dir string, name string) (string, error) {
+	u, err := url.Parse(src)
+	if err != nil {
+		return "", fmt.Errorf("invalid asset %q: %w", src, err)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return "", fmt.Errorf("unsupported asset %q", src)
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, src, nil)
+	if err != nil {
+		return "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
+	if err != nil {
+		return "", fmt.Errorf("failed to download asset: %w", err)
+	}
+	defer resp.Body.Close()
+	if resp.StatusCode != http.StatusOK {
+		return "", fmt.Errorf("failed to download asset, status code: %d", resp.StatusCode)
+	}
+	target := filepath.Join(dir, name+path.Ext(u.Path))
+	file, err := os.Create(target)
+	if err != nil {
+		return "", err
+	}
+	if _, err = io.Copy(file, resp.Body); err != nil {
+		file.Close()
+		return "", fmt.Errorf("failed to download asset: %w", err)
+	}
+	return target, file.Close()
+}
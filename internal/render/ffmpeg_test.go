@@ -0,0 +1,187 @@
+package render
+
+import (
+	"context"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"reflect"
+	"strings"
+	"testing"
+)
+
+func TestEffectFilter(t *testing.T) {
+	crop := "scale=792:1408,crop=720:1280:"
+	tests := []struct {
+		effect string
+		length float64
+		want   string
+	}{
+		{"zoomIn", 4, "zoompan=z='1+0.1*on/120':d=1:x='iw/2-iw/zoom/2':y='ih/2-ih/zoom/2':s=720x1280:fps=30"},
+		{"zoomOut", 4, "zoompan=z='1.1-0.1*on/120':d=1:x='iw/2-iw/zoom/2':y='ih/2-ih/zoom/2':s=720x1280:fps=30"},
+		{"zoomIn", 2.5, "zoompan=z='1+0.1*on/75':d=1:x='iw/2-iw/zoom/2':y='ih/2-ih/zoom/2':s=720x1280:fps=30"},
+		{"slideLeft", 4, crop + "x='72*t/4.000':y='64'"},
+		{"slideRight", 4, crop + "x='72-72*t/4.000':y='64'"},
+		{"slideUp", 4, crop + "x='36':y='128*t/4.000'"},
+		{"slideDown", 4, crop + "x='36':y='128-128*t/4.000'"},
+		{"", 4, ""},
+		{"spin", 4, ""},
+	}
+	for _, tt := range tests {
+		if got := effectFilter(tt.effect, tt.length); got != tt.want {
+			t.Errorf("effectFilter(%q, %v) = %q, want %q", tt.effect, tt.length, got, tt.want)
+		}
+	}
+}
+
+func TestSegmentArgs(t *testing.T) {
+	scale := "scale=720:1280:force_original_aspect_ratio=increase,crop=720:1280,setsar=1,fps=30"
+	encode := []string{"-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p", "out.mp4"}
+	fades := shotstack.Transition{In: "fade", Out: "fade"}
+	tests := []struct {
+		name  string
+		clip  shotstack.Clip
+		first bool
+		last  bool
+		want  []string
+	}{
+		{
+			name:  "first image fades in",
+			clip:  shotstack.Clip{Asset: shotstack.Asset{Type: "image", Src: "in.jpg"}, Length: 3, Transition: fades},
+			first: true,
+			want: append([]string{"-loop", "1", "-t", "3.000", "-i", "in.jpg",
+				"-vf", scale + ",tpad=stop_mode=clone:stop_duration=3.000,fade=t=in:st=0:d=0.500",
+				"-an", "-t", "3.000", "-r", "30"}, encode...),
+		},
+		{
+			name: "last video zooms and fades out",
+			clip: shotstack.Clip{Asset: shotstack.Asset{Type: "video", Src: "in.mp4"}, Length: 5, Effect: "zoomIn", Transition: fades},
+			last: true,
+			want: append([]string{"-t", "5.000", "-i", "in.mp4",
+				"-vf", scale + ",tpad=stop_mode=clone:stop_duration=5.000," + effectFilter("zoomIn", 5) + ",fade=t=out:st=4.500:d=0.500",
+				"-an", "-t", "5.000", "-r", "30"}, encode...),
+		},
+		{
+			name: "middle clip is crossfaded only",
+			clip: shotstack.Clip{Asset: shotstack.Asset{Type: "image", Src: "in.jpg"}, Length: 2, Transition: fades},
+			want: append([]string{"-loop", "1", "-t", "2.000", "-i", "in.jpg",
+				"-vf", scale + ",tpad=stop_mode=clone:stop_duration=2.000",
+				"-an", "-t", "2.000", "-r", "30"}, encode...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := segmentArgs(tt.clip, tt.clip.Asset.Src, "out.mp4", tt.first, tt.last)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("segmentArgs =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestJoinArgs(t *testing.T) {
+	encode := []string{"-c:v", "libx264", "-preset", "veryfast", "-pix_fmt", "yuv420p", "-movflags", "+faststart", "out.mp4"}
+	clips := func(lengths ...int) []shotstack.Clip {
+		result := make([]shotstack.Clip, 0, len(lengths))
+		for _, length := range lengths {
+			result = append(result, shotstack.Clip{Length: length})
+		}
+		return result
+	}
+	tests := []struct {
+		name     string
+		clips    []shotstack.Clip
+		segments []string
+		audio    string
+		want     []string
+	}{
+		{
+			name:     "single clip without music",
+			clips:    clips(3),
+			segments: []string{"s0.mp4"},
+			want:     append([]string{"-i", "s0.mp4", "-map", "0:v", "-t", "3.000"}, encode...),
+		},
+		{
+			name:     "crossfades with music",
+			clips:    clips(3, 4, 2),
+			segments: []string{"s0.mp4", "s1.mp4", "s2.mp4"},
+			audio:    "a.mp3",
+			want: append([]string{"-i", "s0.mp4", "-i", "s1.mp4", "-i", "s2.mp4", "-stream_loop", "-1", "-i", "a.mp3",
+				"-filter_complex", "[0:v][1:v]xfade=transition=fade:duration=1.000:offset=2.000[v1];" +
+					"[v1][2:v]xfade=transition=fade:duration=1.000:offset=5.000[v2];" +
+					"[3:a]atrim=0:7.000,afade=t=in:st=0:d=1,afade=t=out:st=6.000:d=1[a]",
+				"-map", "[v2]", "-map", "[a]", "-c:a", "aac", "-t", "7.000"}, encode...),
+		},
+		{
+			name:     "short clips get shorter transitions",
+			clips:    clips(1, 1),
+			segments: []string{"s0.mp4", "s1.mp4"},
+			want: append([]string{"-i", "s0.mp4", "-i", "s1.mp4",
+				"-filter_complex", "[0:v][1:v]xfade=transition=fade:duration=0.500:offset=0.500[v1]",
+				"-map", "[v1]", "-t", "1.500"}, encode...),
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := joinArgs(tt.clips, tt.segments, tt.audio, "out.mp4")
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("joinArgs =\n%q\nwant\n%q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestFetch(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/story.jpg" {
+			http.NotFound(w, r)
+			return
+		}
+		io.WriteString(w, "image")
+	}))
+	defer server.Close()
+	dir := t.TempDir()
+
+	path, err := fetch(context.Background(), server.URL+"/story.jpg", dir, "asset-0")
+	if err != nil {
+		t.Fatalf("fetch: %v", err)
+	}
+	if path != filepath.Join(dir, "asset-0.jpg") {
+		t.Errorf("path = %q", path)
+	}
+	if content, _ := os.ReadFile(path); string(content) != "image" {
+		t.Errorf("content = %q", content)
+	}
+	if _, err = fetch(context.Background(), server.URL+"/missing.jpg", dir, "asset-1"); err == nil {
+		t.Error("fetch of a missing asset succeeded")
+	}
+}
+
+func TestFetchRejectsLocalFiles(t *testing.T) {
+	local := filepath.Join(t.TempDir(), "secret.txt")
+	if err := os.WriteFile(local, []byte("secret"), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	for _, src := range []string{local, "file://" + local, "ftp://example.com/a.jpg", "data:image/png;base64,AAAA"} {
+		if path, err := fetch(context.Background(), src, t.TempDir(), "asset"); err == nil {
+			t.Errorf("fetch(%q) = %q, want an error", src, path)
+		}
+	}
+}
+
+func TestFromConfigRequiresBaseURL(t *testing.T) {
+	renderer, baseURL := config.Config.Renderer, config.Config.RenderBaseURL
+	defer func() { config.Config.Renderer, config.Config.RenderBaseURL = renderer, baseURL }()
+	config.Config.Renderer, config.Config.RenderBaseURL = "ffmpeg", ""
+
+	if _, err := FromConfig(); err == nil || !strings.Contains(err.Error(), "RENDER_BASE_URL") {
+		t.Errorf("FromConfig = %v, want an error about RENDER_BASE_URL", err)
+	}
+	if _, err := (Local{Dir: t.TempDir()}).Save(context.Background(), "digest.mp4", "digest.mp4"); err == nil {
+		t.Error("Save without a base URL succeeded")
+	}
+}
@@ -0,0 +1,56 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"os/exec"
+)
+
+var ErrNothingToRender = errors.New("no clips to render")
+
+// Renderer makes the recap video of story medias and returns its URL
+type Renderer interface {
+	Render(ctx context.Context, medias []shotstack.Asset) (string, error)
+}
+
+func FromConfig() (Renderer, error) {
+	switch config.Config.Renderer {
+	case "shotstack":
+		return Shotstack{}, nil
+	case "ffmpeg":
+		// Links to videos go to clients and delivery channels, a path on the server is of no use to them
+		if config.Config.RenderBaseURL == "" {
+			return nil, errors.New("ffmpeg renderer: RENDER_BASE_URL is required")
+		}
+		if _, err := exec.LookPath(config.Config.FFmpegPath); err != nil {
+			return nil, fmt.Errorf("ffmpeg renderer: %w", err)
+		}
+		return FFmpeg{
+			Binary:     config.Config.FFmpegPath,
+			Soundtrack: config.Config.RenderSoundtrack,
+			Storage:    Local{Dir: config.Config.RenderDir, BaseURL: config.Config.RenderBaseURL},
+		}, nil
+	}
+	return nil, fmt.Errorf("unknown renderer: %q", config.Config.Renderer)
+}
+
+// Shotstack renders the timeline with the hosted Shotstack API
+type Shotstack struct{}
+
+func (Shotstack) Render(ctx context.Context, medias []shotstack.Asset) (string, error) {
+	data, err := shotstack.GenerateVideoJson(medias)
+	if err != nil {
+		return "", err
+	}
+	id, err := shotstack.GenerateVideo(ctx, data)
+	if err != nil {
+		return "", err
+	}
+	logger.Info("Generated video", zap.String("id", id))
+	return shotstack.GetUrl(ctx, id)
+}
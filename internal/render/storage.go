@@ -0,0 +1,63 @@
+package render
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// Storage keeps rendered videos and returns the URL they can be watched at
+type Storage interface {
+	Save(ctx context.Context, name string, path string) (string, error)
+}
+
+// Local copies videos to Dir, which is served at BaseURL
+type Local struct {
+	Dir     string
+	BaseURL string
+}
+
+func (l Local) Save(ctx context.Context, name string, path string) (string, error) {
+	if l.BaseURL == "" {
+		return "", errors.New("no base URL to serve rendered videos at")
+	}
+	if err := os.MkdirAll(l.Dir, 0o755); err != nil {
+		return "", fmt.Errorf("failed to create render directory: %w", err)
+	}
+	target := filepath.Join(l.Dir, name)
+	if err := copyFile(path, target); err != nil {
+		return "", fmt.Errorf("failed to store video: %w", err)
+	}
+	return strings.TrimSuffix(l.BaseURL, "/") + "/" + name, nil
+}
+
+func copyFile(from string, to string) error {
+	in, err := os.Open(from)
+	if err != nil {
+		return err
+	}
+	defer in.Close()
+	// Written under a temporary name so a half copied video is never served
+	out, err := os.CreateTemp(filepath.Dir(to), ".render-*")
+	if err != nil {
+		return err
+	}
+	if _, err = io.Copy(out, in); err != nil {
+		out.Close()
+		os.Remove(out.Name())
+		return err
+	}
+	if err = out.Close(); err != nil {
+		os.Remove(out.Name())
+		return err
+	}
+	if err = os.Chmod(out.Name(), 0o644); err != nil {
+		os.Remove(out.Name())
+		return err
+	}
+	return os.Rename(out.Name(), to)
+}
@@ -0,0 +1,186 @@
+package scheduler
+
+import (
+	"context"
+	"sync"
+)
+
+type Priority int
+
+const (
+	// OnDemand jobs have a client waiting on the stream, they go first
+	OnDemand Priority = iota
+	// Daily jobs are background digests, they run when no on-demand job is waiting
+	Daily
+)
+
+const lanes = 2
+
+type state int
+
+const (
+	queued state = iota
+	running
+	done
+)
+
+// Ticket is a place of a single job in the scheduler
+type Ticket struct {
+	ID       string
+	weight   int
+	priority Priority
+	state    state
+	ready    chan struct{}
+	moved    chan struct{}
+}
+
+// Scheduler runs at most workers jobs at the same time. Waiting jobs are
+// taken from the highest priority lane first, in FIFO order inside a lane.
+type Scheduler struct {
+	mu      sync.Mutex
+	workers int
+	running int
+	length  int
+	lanes   [lanes][]*Ticket
+}
+
+func New(workers int) *Scheduler {
+	if workers < 1 {
+		workers = 1
+	}
+	return &Scheduler{workers: workers}
+}
+
+// Enqueue adds a job to its lane. Weight is added to the queue length until
+// the job is released with Done.
+func (s *Scheduler) Enqueue(id string, weight int, priority Priority) *Ticket {
+	if priority < 0 || priority >= lanes {
+		priority = OnDemand
+	}
+	t := &Ticket{ID: id, weight: weight, priority: priority, ready: make(chan struct{}), moved: make(chan struct{}, 1)}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.length += weight
+	s.lanes[priority] = append(s.lanes[priority], t)
+	s.dispatch()
+	s.notify()
+	return t
+}
+
+// Wait blocks until the job gets a worker. If ctx is done first the job is
+// taken out of the queue and ctx error is returned. onMove, if not nil, is
+// called with the new position every time the position of the job changes.
+func (s *Scheduler) Wait(ctx context.Context, t *Ticket, onMove func(position int) error) error {
+	for {
+		select {
+		case <-t.ready:
+			return nil
+		case <-t.moved:
+			if onMove == nil {
+				continue
+			}
+			if position := s.Position(t); position >= 0 {
+				if err := onMove(position); err != nil {
+					s.Done(t)
+					return err
+				}
+			}
+		case <-ctx.Done():
+			s.Done(t)
+			return ctx.Err()
+		}
+	}
+}
+
+// Done releases the job, whether it is still waiting or already running.
+// It is safe to call more than once.
+func (s *Scheduler) Done(t *Ticket) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	switch t.state {
+	case queued:
+		s.remove(t)
+	case running:
+		s.running--
+	case done:
+		return
+	}
+	t.state = done
+	s.length -= t.weight
+	s.dispatch()
+	s.notify()
+}
+
+// Position returns how many jobs will get a worker before t, -1 if t is not queued
+func (s *Scheduler) Position(t *Ticket) int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if t.state != queued {
+		return -1
+	}
+	position := 0
+	for priority := range s.lanes {
+		for _, v := range s.lanes[priority] {
+			if v == t {
+				return position
+			}
+			position++
+		}
+	}
+	return -1
+}
+
+// Length returns total weight of queued and running jobs
+func (s *Scheduler) Length() int {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.length
+}
+
+func (s *Scheduler) dispatch() {
+	for s.running < s.workers {
+		t := s.next()
+		if t == nil {
+			return
+		}
+		t.state = running
+		s.running++
+		close(t.ready)
+	}
+}
+
+// notify wakes up every waiting job, their positions may have changed
+func (s *Scheduler) notify() {
+	for priority := range s.lanes {
+		for _, t := range s.lanes[priority] {
+			select {
+			case t.moved <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+func (s *Scheduler) next() *Ticket {
+	for priority := range s.lanes {
+		if len(s.lanes[priority]) > 0 {
+			t := s.lanes[priority][0]
+			s.lanes[priority] = s.lanes[priority][1:]
+			return t
+		}
+	}
+	return nil
+}
+
+func (s *Scheduler) remove(t *Ticket) {
+	lane := s.lanes[t.priority]
+	for i, v := range lane {
+		if v == t {
+			s.lanes[t.priority] = append(lane[:i], lane[i+1:]...)
+			return
+		}
+	}
+}
@@ -0,0 +1,233 @@
+package scheduler
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"sync/atomic"
+	"testing"
+	"time"
+)
+
+// started reports whether t got a worker
+func started(t *Ticket) bool {
+	select {
+	case <-t.ready:
+		return true
+	default:
+		return false
+	}
+}
+
+func TestPriorityAndOrder(t *testing.T) {
+	s := New(1)
+	first := s.Enqueue("first", 1, Daily)
+	daily1 := s.Enqueue("daily1", 1, Daily)
+	onDemand1 := s.Enqueue("onDemand1", 1, OnDemand)
+	daily2 := s.Enqueue("daily2", 1, Daily)
+	onDemand2 := s.Enqueue("onDemand2", 1, OnDemand)
+	if !started(first) {
+		t.Fatal("first job did not get the free worker")
+	}
+
+	// On-demand jobs go first, each lane in FIFO order
+	order := []*Ticket{onDemand1, onDemand2, daily1, daily2}
+	for i, ticket := range order {
+		if position := s.Position(ticket); position != i {
+			t.Errorf("position of %s = %d, want %d", ticket.ID, position, i)
+		}
+	}
+	running := first
+	for i, ticket := range order {
+		s.Done(running)
+		if !started(ticket) {
+			t.Fatalf("%s did not start after %s", ticket.ID, running.ID)
+		}
+		for _, later := range order[i+1:] {
+			if started(later) {
+				t.Errorf("%s started before %s", later.ID, ticket.ID)
+			}
+		}
+		running = ticket
+	}
+}
+
+func TestPositionAndLength(t *testing.T) {
+	s := New(1)
+	running := s.Enqueue("running", 3, OnDemand)
+	queued := s.Enqueue("queued", 2, Daily)
+	if s.Length() != 5 {
+		t.Errorf("length = %d, want the weight of both jobs", s.Length())
+	}
+	if s.Position(running) != -1 || s.Position(queued) != 0 {
+		t.Errorf("positions = %d and %d, want -1 for the running job and 0", s.Position(running), s.Position(queued))
+	}
+	s.Done(running)
+	if s.Length() != 2 || s.Position(queued) != -1 {
+		t.Errorf("length = %d, position = %d, want 2 and the queued job running", s.Length(), s.Position(queued))
+	}
+	s.Done(queued)
+	if s.Length() != 0 {
+		t.Errorf("length = %d after every job is done", s.Length())
+	}
+}
+
+func TestWaitCancelled(t *testing.T) {
+	s := New(1)
+	running := s.Enqueue("running", 1, OnDemand)
+	cancelled := s.Enqueue("cancelled", 1, OnDemand)
+	last := s.Enqueue("last", 1, OnDemand)
+
+	moves := make(chan int, 10)
+	waited := make(chan error, 1)
+	go func() {
+		waited <- s.Wait(context.Background(), last, func(position int) error {
+			moves <- position
+			return nil
+		})
+	}()
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+	if err := s.Wait(ctx, cancelled, nil); !errors.Is(err, context.Canceled) {
+		t.Fatalf("Wait = %v, want context.Canceled", err)
+	}
+	if s.Position(cancelled) != -1 || s.Length() != 2 {
+		t.Errorf("position = %d, length = %d, want the cancelled job out of the queue", s.Position(cancelled), s.Length())
+	}
+	if s.Position(last) != 0 {
+		t.Errorf("position of last = %d, want 0", s.Position(last))
+	}
+	if !waitFor(moves, 0) {
+		t.Error("last job was not told it moved to position 0")
+	}
+
+	s.Done(running)
+	select {
+	case err := <-waited:
+		if err != nil {
+			t.Errorf("Wait of last = %v", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("last job did not start after the running one was done")
+	}
+	if started(cancelled) {
+		t.Error("cancelled job got a worker")
+	}
+}
+
+func TestWaitStopsWhenOnMoveFails(t *testing.T) {
+	s := New(1)
+	running := s.Enqueue("running", 1, OnDemand)
+	first := s.Enqueue("first", 1, OnDemand)
+	second := s.Enqueue("second", 1, OnDemand)
+
+	gone := errors.New("client gone")
+	waited := make(chan error, 1)
+	go func() {
+		waited <- s.Wait(context.Background(), second, func(int) error { return gone })
+	}()
+	s.Done(first)
+	select {
+	case err := <-waited:
+		if !errors.Is(err, gone) {
+			t.Errorf("Wait = %v, want the error of onMove", err)
+		}
+	case <-time.After(time.Second):
+		t.Fatal("Wait did not return after onMove failed")
+	}
+	if s.Position(second) != -1 || s.Length() != 1 {
+		t.Errorf("position = %d, length = %d, want only the running job left", s.Position(second), s.Length())
+	}
+	s.Done(running)
+}
+
+func TestDoneTwice(t *testing.T) {
+	s := New(1)
+	running := s.Enqueue("running", 1, OnDemand)
+	queued := s.Enqueue("queued", 1, OnDemand)
+	waiting1 := s.Enqueue("waiting1", 1, OnDemand)
+	waiting2 := s.Enqueue("waiting2", 1, OnDemand)
+
+	// A queued job released twice leaves the queue once
+	s.Done(queued)
+	s.Done(queued)
+	if s.Length() != 3 || s.Position(waiting1) != 0 || s.Position(waiting2) != 1 {
+		t.Errorf("length = %d, positions = %d, %d, want 3, 0 and 1", s.Length(), s.Position(waiting1), s.Position(waiting2))
+	}
+
+	// A running job released twice frees one worker only
+	s.Done(running)
+	s.Done(running)
+	if !started(waiting1) || started(waiting2) {
+		t.Errorf("started = %v and %v, want only the first waiting job", started(waiting1), started(waiting2))
+	}
+	if s.Length() != 2 {
+		t.Errorf("length = %d, want 2", s.Length())
+	}
+	if started(queued) {
+		t.Error("released job got a worker")
+	}
+}
+
+func TestWorkerLimit(t *testing.T) {
+	const workers = 3
+	s := New(workers)
+	var active, peak atomic.Int32
+	var wg sync.WaitGroup
+	for i := 0; i < 20; i++ {
+		wg.Add(1)
+		go func(i int) {
+			defer wg.Done()
+			priority := OnDemand
+			if i%2 == 0 {
+				priority = Daily
+			}
+			ticket := s.Enqueue(fmt.Sprintf("job-%d", i), 1, priority)
+			defer s.Done(ticket)
+			if err := s.Wait(context.Background(), ticket, nil); err != nil {
+				t.Errorf("Wait: %v", err)
+				return
+			}
+			n := active.Add(1)
+			for {
+				p := peak.Load()
+				if n <= p || peak.CompareAndSwap(p, n) {
+					break
+				}
+			}
+			time.Sleep(5 * time.Millisecond)
+			active.Add(-1)
+		}(i)
+	}
+	wg.Wait()
+	if p := peak.Load(); p > workers || p == 0 {
+		t.Errorf("%d jobs ran at the same time, want at most %d", p, workers)
+	}
+	if s.Length() != 0 {
+		t.Errorf("length = %d after every job is done", s.Length())
+	}
+}
+
+func TestNewNeedsAWorker(t *testing.T) {
+	s := New(0)
+	if ticket := s.Enqueue("job", 1, Priority(7)); !started(ticket) {
+		t.Error("job did not start with workers below 1")
+	}
+}
+
+// waitFor reports whether position is received on moves within a second
+func waitFor(moves <-chan int, position int) bool {
+	timeout := time.After(time.Second)
+	for {
+		select {
+		case p := <-moves:
+			if p == position {
+				return true
+			}
+		case <-timeout:
+			return false
+		}
+	}
+}
@@ -0,0 +1,68 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/inst"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"time"
+)
+
+// Admin lets operators see Instagram accounts and finish logins stopped by a challenge or 2FA
+type Admin struct {
+	grpc.UnimplementedAccountsAdminServer
+	Accounts *inst.Pool
+}
+
+func (a *Admin) ListAccounts(ctx context.Context, req *grpc.AccountsRequest) (*grpc.AccountsResponse, error) {
+	accounts := a.Accounts.Accounts()
+	response := &grpc.AccountsResponse{Accounts: make([]*grpc.AccountStatus, 0, len(accounts))}
+	now := time.Now()
+	for _, account := range accounts {
+		cooldownUntil := ""
+		if account.Health.CooldownUntil.After(now) {
+			cooldownUntil = account.Health.CooldownUntil.Format(time.RFC3339)
+		}
+		response.Accounts = append(response.Accounts, &grpc.AccountStatus{
+			Login:         account.Login,
+			Checkpoint:    account.Health.Checkpoint,
+			CooldownUntil: cooldownUntil,
+			Failures:      int32(account.Health.Failures),
+			LastError:     account.Health.LastError,
+			Pending:       inst.Pending(account.Login),
+		})
+	}
+	return response, nil
+}
+
+func (a *Admin) StartLogin(ctx context.Context, req *grpc.StartLoginRequest) (*grpc.LoginResponse, error) {
+	if req.GetLogin() == "" {
+		return nil, status.Error(codes.InvalidArgument, "login is required")
+	}
+	err := a.Accounts.StartLogin(ctx, req.GetLogin())
+	return loginResponse(req.GetLogin(), err)
+}
+
+func (a *Admin) VerifyLogin(ctx context.Context, req *grpc.VerifyLoginRequest) (*grpc.LoginResponse, error) {
+	if req.GetLogin() == "" || req.GetCode() == "" {
+		return nil, status.Error(codes.InvalidArgument, "login and code are required")
+	}
+	err := a.Accounts.Verify(ctx, req.GetLogin(), req.GetCode())
+	if errors.Is(err, inst.ErrNoPendingLogin) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	return loginResponse(req.GetLogin(), err)
+}
+
+// loginResponse reports a login that is waiting for verification as a response, not an error
+func loginResponse(login string, err error) (*grpc.LoginResponse, error) {
+	if err == nil {
+		return &grpc.LoginResponse{LoggedIn: true}, nil
+	}
+	if pending := inst.Pending(login); pending != "" {
+		return &grpc.LoginResponse{Pending: pending, Message: err.Error()}, nil
+	}
+	return nil, toStatus(err)
+}
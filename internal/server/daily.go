@@ -0,0 +1,116 @@
+package server
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"github.com/google/uuid"
+	"github.com/rendizi/stay-connected-inst/internal/delivery"
+	"github.com/rendizi/stay-connected-inst/internal/format"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"time"
+)
+
+// DailyJob creates the daily digest job of user with its quota left for today
+func DailyJob(ctx context.Context, user *users.User) (*jobs.Job, error) {
+	used, limit, err := users.Usage(ctx, user)
+	if err != nil {
+		return nil, err
+	}
+	if used >= limit {
+		return nil, fmt.Errorf("user %s used %v of %v: %w", user.ID, used, limit, redis.ErrQuotaExceeded)
+	}
+	return jobs.New(fmt.Sprintf("%s", uuid.New()), user.ID, user.Usernames, limit-used, true, user.Preferences), nil
+}
+
+// RunJob runs job without a client and returns once it stopped
+func (s *Server) RunJob(ctx context.Context, job *jobs.Job) error {
+	return s.run(ctx, job, func(*grpc.SummarizeStoriesResponse) error { return nil })
+}
+
+// fingerprint identifies the stories of a fetched profile, it changes when the
+// profile posts or removes a story. It is empty for a profile that was not fetched.
+func fingerprint(profile *inst.Profile) string {
+	if profile == nil {
+		return ""
+	}
+	ids := make([]string, 0, len(profile.Stories))
+	for _, story := range profile.Stories {
+		ids = append(ids, story.Media.ID+":"+story.Media.Version)
+	}
+	sort.Strings(ids)
+	hash := sha256.New()
+	for _, id := range ids {
+		hash.Write([]byte(id + "\n"))
+	}
+	return hex.EncodeToString(hash.Sum(nil))
+}
+
+// GetDailyDigest returns the state of the last daily digest of a user with its job
+func (s *Server) GetDailyDigest(ctx context.Context, req *grpc.UserRequest) (*grpc.DailyDigestResponse, error) {
+	userID, err := callerID(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+	daily, err := jobs.GetDaily(ctx, userID)
+	if errors.Is(err, redis.ErrNotExist) {
+		return nil, status.Errorf(codes.NotFound, "user %s has no daily digest", userID)
+	} else if err != nil {
+		logger.Error("Error loading daily digest", zap.String("user", userID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to load daily digest")
+	}
+	response := &grpc.DailyDigestResponse{Date: daily.Date, Status: daily.Status, Attempts: int32(daily.Attempts), Error: daily.Error}
+	if daily.JobID != "" {
+		job, err := s.Jobs.Get(ctx, daily.JobID)
+		if err == nil {
+			response.Job = toJobResponse(job)
+		} else if !errors.Is(err, redis.ErrNotExist) {
+			logger.Error("Error loading daily digest job", zap.String("id", daily.JobID), zap.Error(err))
+		}
+	}
+	return response, nil
+}
+
+// deliver sends the digest of job to the channels of its user and saves their
+// outcome with the job. It works on a copy, the caller keeps using the job.
+func (s *Server) deliver(ctx context.Context, job jobs.Job) {
+	user, err := users.Get(ctx, job.UserID)
+	if err != nil {
+		logger.Error("Error loading user for delivery", zap.String("user", job.UserID), zap.Error(err))
+		return
+	}
+	if len(user.Channels) == 0 {
+		return
+	}
+	digest := toDigest(&job)
+	body, err := format.ToHTML(digest)
+	if err != nil {
+		logger.Error("Error rendering digest for delivery", zap.String("id", job.ID), zap.Error(err))
+	}
+	job.Deliveries = delivery.Deliver(ctx, user, delivery.Digest{
+		UserID:      user.ID,
+		JobID:       job.ID,
+		Result:      job.Result,
+		LinkToVideo: job.LinkToVideo,
+		Text:        format.ToMarkdown(digest),
+		HTML:        body,
+		CreatedAt:   time.Now(),
+	})
+	if err = s.Jobs.Save(ctx, &job); err != nil {
+		logger.Error("Error saving deliveries of job", zap.String("id", job.ID), zap.Error(err))
+	}
+}
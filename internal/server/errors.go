@@ -0,0 +1,58 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/internal/budget"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/render"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
+	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// code maps errors of the services to gRPC status codes
+func code(err error) codes.Code {
+	if s, ok := status.FromError(err); ok {
+		return s.Code()
+	}
+	switch {
+	case errors.Is(err, context.Canceled):
+		return codes.Canceled
+	case errors.Is(err, context.DeadlineExceeded), errors.Is(err, shotstack.ErrRenderTimeout):
+		return codes.DeadlineExceeded
+	case errors.Is(err, inst.ErrChallengeRequired):
+		return codes.FailedPrecondition
+	case errors.Is(err, inst.ErrLoginFailed), errors.Is(err, inst.ErrNoAccounts), errors.Is(err, resilience.ErrUnavailable):
+		return codes.Unavailable
+	case errors.Is(err, shotstack.ErrRenderFailed), errors.Is(err, render.ErrFFmpeg), errors.Is(err, reply.ErrInvalid):
+		return codes.Internal
+	case errors.Is(err, redis.ErrQuotaExceeded), errors.Is(err, budget.ErrExhausted):
+		return codes.ResourceExhausted
+	case errors.Is(err, redis.ErrNotExist):
+		return codes.NotFound
+	}
+	return codes.Unknown
+}
+
+// toStatus converts err to a gRPC status error, nil stays nil
+func toStatus(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+	return status.Error(code(err), err.Error())
+}
+
+// ErrorFrom is an error event for err with its status code
+func ErrorFrom(err error) *grpc.SummarizeStoriesResponse {
+	event := Error(err.Error())
+	event.GetError().Code = int32(code(err))
+	return event
+}
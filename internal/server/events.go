@@ -0,0 +1,67 @@
+package server
+
+import (
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+)
+
+func QueuePosition(position int) *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_QueuePosition{
+		QueuePosition: &grpc.QueuePosition{Position: int32(position)},
+	}}
+}
+
+func LoggedIn() *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_LoggedIn{
+		LoggedIn: &grpc.LoggedIn{},
+	}}
+}
+
+func ProfileStarted(username string) *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_ProfileStarted{
+		ProfileStarted: &grpc.ProfileStarted{Username: username},
+	}}
+}
+
+func ProfileFinished(username string, summary string, skipped bool, skippedStories int) *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_ProfileFinished{
+		ProfileFinished: &grpc.ProfileFinished{Username: username, Summary: summary, Skipped: skipped, SkippedStories: int32(skippedStories)},
+	}}
+}
+
+func StorySummary(username string, summary string, mediaType string, addedToVideo bool) *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_StorySummary{
+		StorySummary: &grpc.StorySummary{Username: username, Summary: summary, MediaType: mediaType, AddedToVideo: addedToVideo},
+	}}
+}
+
+func UsageDelta(delta float32, used float32) *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_UsageDelta{
+		UsageDelta: &grpc.UsageDelta{Delta: delta, Used: used},
+	}}
+}
+
+// Digest is the final event of a job with usage and skipped stories of every username.
+// Legacy fields are filled too for older clients.
+func Digest(result string, linkToVideo string, used float32, results []jobs.Result) *grpc.SummarizeStoriesResponse {
+	usage := make([]*grpc.Usage, 0, len(results))
+	skipped := 0
+	for _, r := range results {
+		usage = append(usage, &grpc.Usage{Username: r.Username, Used: r.Used, Cached: int32(r.Cached), Refunded: r.Refunded, SkippedStories: int32(r.SkippedStories)})
+		skipped += r.SkippedStories
+	}
+	return &grpc.SummarizeStoriesResponse{
+		Result:      result,
+		LinkToVideo: linkToVideo,
+		Used:        used,
+		Event: &grpc.SummarizeStoriesResponse_Digest{
+			Digest: &grpc.Digest{Result: result, LinkToVideo: linkToVideo, Used: used, Usage: usage, SkippedStories: int32(skipped)},
+		},
+	}
+}
+
+func Error(message string) *grpc.SummarizeStoriesResponse {
+	return &grpc.SummarizeStoriesResponse{Event: &grpc.SummarizeStoriesResponse_Error{
+		Error: &grpc.Error{Message: message},
+	}}
+}
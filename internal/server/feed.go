@@ -0,0 +1,140 @@
+package server
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/format"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"net/http"
+	"net/url"
+	"strings"
+	"time"
+)
+
+// How many digests a feed lists
+const feedDigests = 30
+
+// toDigest collects what formatters need from a finished job
+func toDigest(job *jobs.Job) format.Digest {
+	digest := format.Digest{
+		ID:          job.ID,
+		Title:       "Stories digest " + job.UpdatedAt.UTC().Format("2006-01-02"),
+		CreatedAt:   job.UpdatedAt,
+		LinkToVideo: job.LinkToVideo,
+	}
+	for _, result := range job.Results {
+		if result.Skipped {
+			continue
+		}
+		entry := format.Entry{Username: result.Username, Summary: result.Summary}
+		for _, story := range result.Stories {
+			entry.Stories = append(entry.Stories, format.Story{
+				ID:        story.ID,
+				Type:      story.Type,
+				Link:      story.Link,
+				Thumbnail: story.Thumbnail,
+				TakenAt:   story.TakenAt,
+				Summary:   story.Summary,
+			})
+		}
+		digest.Entries = append(digest.Entries, entry)
+	}
+	return digest
+}
+
+// formatted renders the digest of job in the format the client asked for,
+// the JSON result is kept when that fails
+func formatted(job *jobs.Job) string {
+	if job.Format == "" || job.Format == format.JSON {
+		return job.Result
+	}
+	result, err := format.Render(job.Format, toDigest(job))
+	if err != nil {
+		logger.Error("Error formatting digest", zap.String("id", job.ID), zap.String("format", job.Format), zap.Error(err))
+		return job.Result
+	}
+	return result
+}
+
+// FeedToken signs the feed of a user, feed readers can not send credentials
+// so the token in the URL is what lets them in
+func FeedToken(userID string) string {
+	mac := hmac.New(sha256.New, []byte(config.Config.FeedSecret))
+	mac.Write([]byte(userID))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// FeedURL returns the Atom feed of a user, empty when feeds are disabled
+func FeedURL(userID string) string {
+	if config.Config.FeedSecret == "" || config.Config.FeedBaseURL == "" {
+		return ""
+	}
+	return strings.TrimSuffix(config.Config.FeedBaseURL, "/") + "/feeds/" + url.PathEscape(userID) + ".atom?token=" + FeedToken(userID)
+}
+
+var feedFormats = map[string]string{".atom": format.Atom, ".rss": format.RSS, ".json": format.JSONFeed}
+
+// FeedHandler serves the digests of a user at /feeds/<user>.<atom|rss|json>?token=<token>
+// and recap videos of the local renderer at /renders/
+func FeedHandler() http.Handler {
+	mux := http.NewServeMux()
+	mux.HandleFunc("/feeds/", func(w http.ResponseWriter, r *http.Request) {
+		if r.Method != http.MethodGet && r.Method != http.MethodHead {
+			http.Error(w, "method not allowed", http.StatusMethodNotAllowed)
+			return
+		}
+		name := strings.TrimPrefix(r.URL.Path, "/feeds/")
+		var userID, feedFormat string
+		for extension, f := range feedFormats {
+			if strings.HasSuffix(name, extension) {
+				userID, feedFormat = strings.TrimSuffix(name, extension), f
+			}
+		}
+		if userID == "" || config.Config.FeedSecret == "" {
+			http.NotFound(w, r)
+			return
+		}
+		if !hmac.Equal([]byte(r.URL.Query().Get("token")), []byte(FeedToken(userID))) {
+			http.Error(w, "invalid token", http.StatusForbidden)
+			return
+		}
+
+		list, err := jobs.OfUser(r.Context(), userID, feedDigests)
+		if err != nil {
+			logger.Error("Error loading jobs for feed", zap.String("user", userID), zap.Error(err))
+			http.Error(w, "failed to load digests", http.StatusInternalServerError)
+			return
+		}
+		feed := format.Feed{Title: "Stories digests of " + userID, ID: "urn:feed:" + userID, Link: FeedURL(userID), Updated: time.Now()}
+		if len(list) > 0 {
+			feed.Updated = list[0].UpdatedAt
+		}
+		for _, job := range list {
+			feed.Digests = append(feed.Digests, toDigest(job))
+		}
+		data, err := format.RenderFeed(feedFormat, feed)
+		if err != nil {
+			logger.Error("Error rendering feed", zap.String("user", userID), zap.Error(err))
+			http.Error(w, "failed to render feed", http.StatusInternalServerError)
+			return
+		}
+		w.Header().Set("Content-Type", format.ContentType(feedFormat))
+		w.Write(data)
+	})
+	// Recap videos of the local renderer, names are random so the directory is never listed
+	if config.Config.Renderer == "ffmpeg" {
+		files := http.StripPrefix("/renders/", http.FileServer(http.Dir(config.Config.RenderDir)))
+		mux.HandleFunc("/renders/", func(w http.ResponseWriter, r *http.Request) {
+			if strings.HasSuffix(r.URL.Path, "/") {
+				http.NotFound(w, r)
+				return
+			}
+			files.ServeHTTP(w, r)
+		})
+	}
+	return mux
+}
@@ -0,0 +1,203 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/internal/auth"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/history"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"sort"
+	"strconv"
+	"strings"
+	"time"
+)
+
+const (
+	defaultPageSize = 20
+	maxPageSize     = 100
+)
+
+// callerID returns the user a request acts for. Callers act for themselves,
+// admins and unauthenticated servers for the requested user.
+func callerID(ctx context.Context, requested string) (string, error) {
+	if identity, ok := auth.FromContext(ctx); ok && !identity.Admin {
+		if requested != "" && requested != identity.Subject {
+			return "", status.Error(codes.PermissionDenied, "user id does not match the caller")
+		}
+		return identity.Subject, nil
+	}
+	return requested, nil
+}
+
+// historyRecord is a record of username with what matters for sorting and paging
+type historyRecord struct {
+	username string
+	record   history.Record
+}
+
+func (s *Server) GetHistory(ctx context.Context, req *grpc.GetHistoryRequest) (*grpc.HistoryResponse, error) {
+	userID, err := callerID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	username := req.GetUsername()
+	if username == "" {
+		return nil, status.Error(codes.InvalidArgument, "username is required")
+	}
+	from, to, err := dateRange(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+	if userID != "" {
+		user, err := getUser(ctx, userID)
+		if err != nil {
+			return nil, err
+		}
+		if !user.Tracks(username) {
+			return nil, status.Errorf(codes.PermissionDenied, "%s is not tracked by user %s", username, userID)
+		}
+		if from, err = retained(user, from); err != nil {
+			return nil, err
+		}
+	}
+
+	past, err := history.Get(ctx, username)
+	if err != nil {
+		logger.Error("Error loading history", zap.String("username", username), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to load history")
+	}
+	records := make([]historyRecord, 0)
+	for _, record := range past.Between(from, to) {
+		records = append(records, historyRecord{username: username, record: record})
+	}
+	return page(records, req.GetPageSize(), req.GetPageToken())
+}
+
+// SearchHistory finds records of the usernames a user tracks that contain every word of the query
+func (s *Server) SearchHistory(ctx context.Context, req *grpc.SearchHistoryRequest) (*grpc.HistoryResponse, error) {
+	userID, err := callerID(ctx, req.GetUserId())
+	if err != nil {
+		return nil, err
+	}
+	if userID == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+	terms := strings.Fields(strings.ToLower(req.GetQuery()))
+	if len(terms) == 0 {
+		return nil, status.Error(codes.InvalidArgument, "query is required")
+	}
+	from, to, err := dateRange(req.GetFrom(), req.GetTo())
+	if err != nil {
+		return nil, err
+	}
+	user, err := getUser(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if from, err = retained(user, from); err != nil {
+		return nil, err
+	}
+
+	records := make([]historyRecord, 0)
+	for _, username := range user.Usernames {
+		past, err := history.Get(ctx, username)
+		if err != nil {
+			logger.Error("Error loading history", zap.String("username", username), zap.Error(err))
+			continue
+		}
+		for _, record := range past.Between(from, to) {
+			if matches(record, terms) {
+				records = append(records, historyRecord{username: username, record: record})
+			}
+		}
+	}
+	sort.SliceStable(records, func(i, j int) bool {
+		if records[i].record.Date != records[j].record.Date {
+			return records[i].record.Date > records[j].record.Date
+		}
+		return records[i].username < records[j].username
+	})
+	return page(records, req.GetPageSize(), req.GetPageToken())
+}
+
+func matches(record history.Record, terms []string) bool {
+	text := strings.ToLower(record.Summary + " " + strings.Join(record.Tags, " "))
+	for _, term := range terms {
+		if !strings.Contains(text, term) {
+			return false
+		}
+	}
+	return true
+}
+
+func dateRange(from string, to string) (string, string, error) {
+	for _, date := range []string{from, to} {
+		if date == "" {
+			continue
+		}
+		if _, err := time.Parse(history.DateLayout, date); err != nil {
+			return "", "", status.Errorf(codes.InvalidArgument, "invalid date %q, expected YYYY-MM-DD", date)
+		}
+	}
+	if from != "" && to != "" && from > to {
+		return "", "", status.Error(codes.InvalidArgument, "from is after to")
+	}
+	return from, to, nil
+}
+
+// retained moves from forward to the oldest day the plan of user may read
+func retained(user *users.User, from string) (string, error) {
+	plan, err := users.PlanOf(user.Plan)
+	if errors.Is(err, users.ErrUnknownPlan) {
+		return "", status.Error(codes.FailedPrecondition, err.Error())
+	} else if err != nil {
+		return "", status.Error(codes.Internal, err.Error())
+	}
+	oldest := history.Since(plan.HistoryDays, time.Now())
+	if from < oldest {
+		return oldest, nil
+	}
+	return from, nil
+}
+
+// page returns records from the offset in token, the next token is empty on the last page
+func page(records []historyRecord, size int32, token string) (*grpc.HistoryResponse, error) {
+	if size <= 0 {
+		size = defaultPageSize
+	} else if size > maxPageSize {
+		size = maxPageSize
+	}
+	offset := 0
+	if token != "" {
+		var err error
+		if offset, err = strconv.Atoi(token); err != nil || offset < 0 {
+			return nil, status.Error(codes.InvalidArgument, "invalid page token")
+		}
+	}
+	response := &grpc.HistoryResponse{Records: make([]*grpc.HistoryRecord, 0, size)}
+	end := offset + int(size)
+	if end > len(records) {
+		end = len(records)
+	}
+	for i := offset; i < end; i++ {
+		r := records[i]
+		response.Records = append(response.Records, &grpc.HistoryRecord{
+			Username:   r.username,
+			Date:       r.record.Date,
+			Summary:    r.record.Summary,
+			StoryIds:   r.record.StoryIDs,
+			MediaTypes: r.record.MediaTypes,
+			Tags:       r.record.Tags,
+			CreatedAt:  r.record.CreatedAt.Unix(),
+		})
+	}
+	if end < len(records) {
+		response.NextPageToken = strconv.Itoa(end)
+	}
+	return response, nil
+}
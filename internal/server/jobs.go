@@ -0,0 +1,219 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/internal/auth"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"sync"
+)
+
+// attachment fans events of a running job out to clients that reattached with ResumeJob
+type attachment struct {
+	mu          sync.Mutex
+	subscribers map[chan *grpc.SummarizeStoriesResponse]struct{}
+}
+
+// publish never blocks the job, a subscriber that does not keep up loses progress events
+func (a *attachment) publish(event *grpc.SummarizeStoriesResponse) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for ch := range a.subscribers {
+		select {
+		case ch <- proto.Clone(event).(*grpc.SummarizeStoriesResponse):
+		default:
+		}
+	}
+}
+
+func (s *Server) attach(id string) (*attachment, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if _, ok := s.attached[id]; ok {
+		return nil, false
+	}
+	a := &attachment{subscribers: make(map[chan *grpc.SummarizeStoriesResponse]struct{})}
+	s.attached[id] = a
+	return a, true
+}
+
+func (s *Server) detach(id string) {
+	s.mu.Lock()
+	a := s.attached[id]
+	delete(s.attached, id)
+	s.mu.Unlock()
+
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	for ch := range a.subscribers {
+		close(ch)
+	}
+	a.subscribers = nil
+}
+
+func (s *Server) subscribe(id string) (chan *grpc.SummarizeStoriesResponse, bool) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	a, ok := s.attached[id]
+	if !ok {
+		return nil, false
+	}
+	ch := make(chan *grpc.SummarizeStoriesResponse, 64)
+	a.mu.Lock()
+	a.subscribers[ch] = struct{}{}
+	a.mu.Unlock()
+	return ch, true
+}
+
+func (s *Server) unsubscribe(id string, ch chan *grpc.SummarizeStoriesResponse) {
+	s.mu.Lock()
+	a, ok := s.attached[id]
+	s.mu.Unlock()
+	if !ok {
+		return
+	}
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if _, ok := a.subscribers[ch]; ok {
+		delete(a.subscribers, ch)
+		close(ch)
+	}
+}
+
+// ResumeActive runs again jobs that were queued or running when the server stopped
+func (s *Server) ResumeActive(ctx context.Context) {
+	active, err := jobs.Active(ctx)
+	if err != nil {
+		logger.Error("Error loading active jobs", zap.Error(err))
+		return
+	}
+	for _, job := range active {
+		logger.Info("Resuming job", zap.String("id", job.ID), zap.Strings("remaining", job.Remaining()))
+		go func(job *jobs.Job) {
+			err := s.run(ctx, job, func(*grpc.SummarizeStoriesResponse) error { return nil })
+			if err != nil {
+				logger.Error("Error resuming job", zap.String("id", job.ID), zap.Error(err))
+			}
+		}(job)
+	}
+}
+
+func (s *Server) GetJob(ctx context.Context, req *grpc.JobRequest) (*grpc.JobResponse, error) {
+	job, err := s.getJob(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toJobResponse(job), nil
+}
+
+func toJobResponse(job *jobs.Job) *grpc.JobResponse {
+	deliveries := make([]*grpc.DeliveryStatus, 0, len(job.Deliveries))
+	for _, d := range job.Deliveries {
+		deliveries = append(deliveries, &grpc.DeliveryStatus{
+			Channel:   d.Channel,
+			Target:    d.Target,
+			Delivered: d.Delivered,
+			Attempts:  int32(d.Attempts),
+			Error:     d.Error,
+			At:        d.At.Unix(),
+		})
+	}
+	results := make([]*grpc.JobResult, 0, len(job.Results))
+	for _, result := range job.Results {
+		results = append(results, &grpc.JobResult{Username: result.Username, Summary: result.Summary, Skipped: result.Skipped, SkippedStories: int32(result.SkippedStories), Used: result.Used, Cached: int32(result.Cached), Refunded: result.Refunded})
+	}
+	return &grpc.JobResponse{
+		Id:          job.ID,
+		Status:      string(job.Status),
+		Usernames:   job.Usernames,
+		Results:     results,
+		Used:        job.Used,
+		Result:      job.Result,
+		LinkToVideo: job.LinkToVideo,
+		Error:       job.Error,
+		Deliveries:  deliveries,
+	}
+}
+
+// ResumeJob streams events of a job. A job running on this server is followed,
+// a finished one gets its digest sent again, any other job is run from where it stopped.
+func (s *Server) ResumeJob(req *grpc.JobRequest, stream grpc.StoriesSummarizer_ResumeJobServer) error {
+	ctx := stream.Context()
+	id := req.GetId()
+	// Only the owner of the job may follow it
+	if _, err := s.getJob(ctx, id); err != nil {
+		return err
+	}
+
+	if ch, ok := s.subscribe(id); ok {
+		defer s.unsubscribe(id, ch)
+		for {
+			select {
+			case event, ok := <-ch:
+				if !ok {
+					return s.sendFinal(ctx, id, stream)
+				}
+				if event.GetDigest() != nil {
+					return stream.Send(event)
+				}
+				if err := stream.Send(event); err != nil {
+					return err
+				}
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+		}
+	}
+
+	job, err := s.getJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status == jobs.Done {
+		return stream.Send(jobDigest(job))
+	}
+	return toStatus(s.run(ctx, job, stream.Send))
+}
+
+func (s *Server) sendFinal(ctx context.Context, id string, stream grpc.StoriesSummarizer_ResumeJobServer) error {
+	job, err := s.getJob(ctx, id)
+	if err != nil {
+		return err
+	}
+	if job.Status == jobs.Done {
+		return stream.Send(jobDigest(job))
+	}
+	event := Error("Job " + string(job.Status) + ": " + job.Error)
+	event.JobId = job.ID
+	return stream.Send(event)
+}
+
+func (s *Server) getJob(ctx context.Context, id string) (*jobs.Job, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "job id is required")
+	}
+	job, err := s.Jobs.Get(ctx, id)
+	if errors.Is(err, redis.ErrNotExist) {
+		return nil, status.Error(codes.NotFound, err.Error())
+	}
+	if err != nil {
+		return nil, status.Error(codes.Internal, err.Error())
+	}
+	if identity, ok := auth.FromContext(ctx); ok && !identity.Admin && identity.Subject != job.UserID {
+		return nil, status.Errorf(codes.NotFound, "job %s: %v", id, redis.ErrNotExist)
+	}
+	return job, nil
+}
+
+func jobDigest(job *jobs.Job) *grpc.SummarizeStoriesResponse {
+	event := Digest(formatted(job), job.LinkToVideo, job.Used, job.Results)
+	event.JobId = job.ID
+	return event
+}
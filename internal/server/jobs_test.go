@@ -0,0 +1,50 @@
+package server
+
+import (
+	"context"
+	"github.com/rendizi/stay-connected-inst/internal/auth"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"testing"
+)
+
+func TestGetJobOwner(t *testing.T) {
+	s := newTestServer()
+	job := jobs.New("job-1", "alice", []string{"bob"}, 10, false, "news")
+	if err := s.Jobs.Save(context.Background(), job); err != nil {
+		t.Fatal(err)
+	}
+
+	tests := []struct {
+		name     string
+		identity *auth.Identity
+		code     codes.Code
+	}{
+		{"owner", &auth.Identity{Subject: "alice", Method: auth.APIKey}, codes.OK},
+		{"other user", &auth.Identity{Subject: "mallory", Method: auth.APIKey}, codes.NotFound},
+		{"admin", &auth.Identity{Subject: "root", Method: auth.APIKey, Admin: true}, codes.OK},
+		{"auth disabled", nil, codes.OK},
+	}
+	for _, tt := range tests {
+		ctx := context.Background()
+		if tt.identity != nil {
+			ctx = auth.NewContext(ctx, tt.identity)
+		}
+		response, err := s.GetJob(ctx, &grpc.JobRequest{Id: job.ID})
+		if code := status.Code(err); code != tt.code {
+			t.Errorf("%s: GetJob = %v, want %s", tt.name, err, tt.code)
+			continue
+		}
+		if err == nil && response.Id != job.ID {
+			t.Errorf("%s: got job %s", tt.name, response.Id)
+		}
+	}
+
+	// Another user can not tell a job of someone else from a missing one
+	ctx := auth.NewContext(context.Background(), &auth.Identity{Subject: "mallory"})
+	if _, err := s.GetJob(ctx, &grpc.JobRequest{Id: "job-2"}); status.Code(err) != codes.NotFound {
+		t.Errorf("GetJob of a missing job = %v, want NotFound", err)
+	}
+}
@@ -0,0 +1,51 @@
+package server
+
+import (
+	"context"
+	"sync"
+)
+
+// parallel calls fn for every index below n with at most workers calls at a
+// time. The first error cancels ctx of the other calls and is returned.
+func parallel(ctx context.Context, n int, workers int, fn func(ctx context.Context, i int) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	if workers < 1 {
+		workers = 1
+	}
+
+	var wg sync.WaitGroup
+	var once sync.Once
+	var first error
+	indexes := make(chan int)
+	for w := 0; w < workers && w < n; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for i := range indexes {
+				if err := fn(ctx, i); err != nil {
+					once.Do(func() {
+						first = err
+						cancel()
+					})
+				}
+			}
+		}()
+	}
+
+feed:
+	for i := 0; i < n; i++ {
+		select {
+		case indexes <- i:
+		case <-ctx.Done():
+			break feed
+		}
+	}
+	close(indexes)
+	wg.Wait()
+
+	if first != nil {
+		return first
+	}
+	return ctx.Err()
+}
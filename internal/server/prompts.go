@@ -0,0 +1,31 @@
+package server
+
+import (
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/prompts"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+)
+
+// storyPrompt builds the prompt for a single story media and returns it with its
+// version. previous are summarizes of earlier stories of the same profile,
+// history is the last 7 days of the profile.
+func storyPrompt(story inst.Story, isBusiness bool, previous []openai.StoriesType, history string) (string, string, error) {
+	media := "image"
+	if story.Media.Type == inst.Video {
+		media = "video"
+	}
+	return prompts.Render(prompts.StoryPrompt, isBusiness, prompts.Story{
+		Username:  story.Username,
+		Media:     media,
+		Previous:  fmt.Sprintf("%s", previous),
+		History:   history,
+		Events:    story.Events,
+		Hashtags:  story.Hashtags,
+		Polls:     story.Polls,
+		Locations: story.Locations,
+		Questions: story.Questions,
+		Sliders:   story.Sliders,
+		Mentions:  story.Mentions,
+	})
+}
@@ -3,329 +3,653 @@ package server
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"github.com/google/uuid"
 	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/auth"
+	"github.com/rendizi/stay-connected-inst/internal/budget"
+	"github.com/rendizi/stay-connected-inst/internal/cache"
+	"github.com/rendizi/stay-connected-inst/internal/format"
 	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/history"
 	inst2 "github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
 	"github.com/rendizi/stay-connected-inst/internal/redis"
-	"github.com/rendizi/stay-connected-inst/internal/services/gemini"
+	"github.com/rendizi/stay-connected-inst/internal/render"
+	"github.com/rendizi/stay-connected-inst/internal/scheduler"
 	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
 	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"github.com/rendizi/stay-connected-inst/internal/services/summarizer"
+	"github.com/rendizi/stay-connected-inst/internal/users"
 	"github.com/rendizi/stay-connected-inst/pkg/logger"
 	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"log"
+	"strings"
+	"sync"
 	"time"
 )
 
 type Server struct {
 	grpc.UnimplementedStoriesSummarizerServer
+	Images summarizer.Summarizer
+	Videos summarizer.Summarizer
+	Merge  summarizer.Summarizer
+	Queue  *scheduler.Scheduler
+	// Renderer makes the recap video of daily jobs
+	Renderer render.Renderer
+	// Accounts is the pool of Instagram accounts jobs visit profiles with,
+	// nil when stories come from fixtures
+	Accounts *inst2.Pool
+	// NewFetcher gives a story fetcher for a job
+	NewFetcher func() inst2.StoryFetcher
+	History    HistoryStore
+	Cache      SummaryCache
+	Jobs       JobStore
+
+	mu       sync.Mutex
+	attached map[string]*attachment
+}
+
+func New() (*Server, error) {
+	images, videos, merge, err := summarizer.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+	renderer, err := render.FromConfig()
+	if err != nil {
+		return nil, err
+	}
+	server := &Server{Images: images, Videos: videos, Merge: merge, Renderer: renderer, Queue: scheduler.New(config.Config.Workers), attached: make(map[string]*attachment)}
+	server.History, server.Cache, server.Jobs = redisHistory{}, redisCache{}, redisJobs{}
+	switch config.Config.StoryFetcher {
+	case "instagram":
+		server.Accounts, err = inst2.NewPool(context.Background())
+		if err != nil {
+			return nil, err
+		}
+		server.NewFetcher = server.Accounts.Fetcher
+	case "fixtures":
+		server.NewFetcher = func() inst2.StoryFetcher {
+			return inst2.FixtureFetcher{Dir: config.Config.FixturesDir}
+		}
+	default:
+		return nil, fmt.Errorf("unknown story fetcher: %q", config.Config.StoryFetcher)
+	}
+	return server, nil
 }
 
 func (s *Server) QueueLength(ctx context.Context, req *grpc.QueueLengthRequest) (*grpc.QueueLengthResponse, error) {
-	length := config.GetQueueLength()
+	length := s.Queue.Length()
 	return &grpc.QueueLengthResponse{Response: float32(length)}, nil
 }
 
+// CacheStats returns how often summaries were served from the cache instead of a provider
+func (s *Server) CacheStats(ctx context.Context, req *grpc.CacheStatsRequest) (*grpc.CacheStatsResponse, error) {
+	hits, misses, err := cache.Stats(ctx)
+	if err != nil {
+		logger.Error("Error reading cache stats", zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to read cache stats")
+	}
+	return &grpc.CacheStatsResponse{Hits: hits, Misses: misses}, nil
+}
+
 func (s *Server) SummarizeStories(req *grpc.SummarizeStoriesRequest, stream grpc.StoriesSummarizer_SummarizeStoriesServer) error {
+	ctx := stream.Context()
 	isDaily := req.IsDaily
 	usernames := req.Usernames
 	logger.Info(fmt.Sprintf("%v", usernames))
 	left := req.GetLeft()
 	preferences := req.UserPreferences
+	userID := req.GetUserId()
+	if identity, ok := auth.FromContext(ctx); ok && !identity.Admin {
+		// Callers act for themselves, only admins run jobs of other users
+		if userID != "" && userID != identity.Subject {
+			return status.Error(codes.PermissionDenied, "user id does not match the caller")
+		}
+		userID = identity.Subject
+	}
+	if userID != "" {
+		// Usernames, preferences and quota of a known user come from the server, never from the request
+		user, err := users.Get(ctx, userID)
+		if errors.Is(err, redis.ErrNotExist) {
+			return stream.Send(Error("Unknown user"))
+		} else if err != nil {
+			logger.Error("Error loading user", zap.String("user", userID), zap.Error(err))
+			return status.Error(codes.Internal, "failed to load user")
+		}
+		usernames = tracked(user.Usernames, usernames)
+		preferences = user.Preferences
+		used, limit, err := users.Usage(ctx, user)
+		if err != nil {
+			logger.Error("Error loading usage of user", zap.String("user", userID), zap.Error(err))
+			return status.Error(codes.Internal, "failed to load usage")
+		}
+		left = limit - used
+	} else if !config.Config.AllowAnonymous {
+		return stream.Send(Error("A user id is required"))
+	}
 	if len(preferences) == 0 {
 		return nil
 	}
 	if left <= 0 {
-		if err := stream.Send(Format("You have reacher your usage limit")); err != nil {
+		if err := stream.Send(Error("You have reached your usage limit")); err != nil {
 			return err
 		}
 		return nil
 	}
 	if len(usernames) <= 0 {
-		if err := stream.Send(Format("No usernames has been provided")); err != nil {
+		if err := stream.Send(Error("No usernames has been provided")); err != nil {
 			return err
 		}
 		return nil
 	}
-	id := fmt.Sprintf("%s", uuid.New())
-	config.Enqueue(id, len(usernames))
-	defer config.RemoveFromQueue(id, len(usernames))
-	if err := stream.Send(Format("Queued")); err != nil {
-		return err
+	if !format.Valid(req.GetFormat()) {
+		return stream.Send(Error("Unknown format " + req.GetFormat()))
 	}
-	signal := make(chan struct{})
-	storiesArray := make([]openai.StoriesType, 0)
+	job := jobs.New(fmt.Sprintf("%s", uuid.New()), userID, usernames, left, isDaily, preferences)
+	job.Format = req.GetFormat()
+	return toStatus(s.run(ctx, job, stream.Send))
+}
 
-	go func() {
-		defer close(signal)
-		for {
-			if config.NextInQueue() == id {
-				signal <- struct{}{}
-				return
+// tracked returns requested usernames the user tracks, all of them when nothing was requested
+func tracked(usernames []string, requested []string) []string {
+	if len(requested) == 0 {
+		return usernames
+	}
+	tracks := make(map[string]bool, len(usernames))
+	for _, username := range usernames {
+		tracks[username] = true
+	}
+	result := make([]string, 0, len(requested))
+	for _, username := range requested {
+		if tracks[username] {
+			result = append(result, username)
+		}
+	}
+	return result
+}
+
+// run executes the job from the first username without a result. Progress is
+// saved after every username, so an interrupted job can be run again later.
+func (s *Server) run(ctx context.Context, job *jobs.Job, send func(*grpc.SummarizeStoriesResponse) error) (err error) {
+	a, ok := s.attach(job.ID)
+	if !ok {
+		return status.Errorf(codes.AlreadyExists, "job %s is already running", job.ID)
+	}
+	defer s.detach(job.ID)
+	owner := send
+	send = func(event *grpc.SummarizeStoriesResponse) error {
+		event.JobId = job.ID
+		a.publish(event)
+		return owner(event)
+	}
+
+	job.Status = jobs.Queued
+	job.Error = ""
+	if err = s.Jobs.Save(ctx, job); err != nil {
+		logger.Error("Error saving job", zap.String("id", job.ID), zap.Error(err))
+	}
+	defer func() {
+		switch {
+		case err == nil:
+			job.Status = jobs.Done
+		case ctx.Err() != nil:
+			job.Status = jobs.Cancelled
+		default:
+			job.Status = jobs.Failed
+			job.Error = err.Error()
+		}
+		if err := s.Jobs.Save(context.Background(), job); err != nil {
+			logger.Error("Error saving job", zap.String("id", job.ID), zap.Error(err))
+		}
+		if job.Status == jobs.Done && job.UserID != "" && !job.Unchanged {
+			if err := s.Jobs.AddToUser(context.Background(), job); err != nil {
+				logger.Error("Error listing job of user", zap.String("id", job.ID), zap.Error(err))
 			}
-			time.Sleep(500 * time.Millisecond)
+		}
+		// Daily digests are delivered even when the client is gone, in the
+		// background so the stream ends with the digest. On-demand digests
+		// are only streamed.
+		if job.IsDaily && job.Result != "" && job.UserID != "" && len(job.Deliveries) == 0 {
+			go s.deliver(context.WithoutCancel(ctx), *job)
 		}
 	}()
-	<-signal
 
-	inst, err := inst2.Login(config.Config.DefaultLogin, config.Config.DefaultPassword)
+	isDaily := job.IsDaily
+	preferences := job.Preferences
+	priority := scheduler.OnDemand
+	if isDaily {
+		priority = scheduler.Daily
+	}
+	ticket := s.Queue.Enqueue(job.ID, len(job.Remaining()), priority)
+	defer s.Queue.Done(ticket)
+	if err := send(QueuePosition(s.Queue.Position(ticket))); err != nil {
+		return err
+	}
+
+	err = s.Queue.Wait(ctx, ticket, func(position int) error {
+		return send(QueuePosition(position))
+	})
+	if err != nil {
+		return err
+	}
+	job.Status = jobs.Running
+	if err = s.Jobs.Save(ctx, job); err != nil {
+		logger.Error("Error saving job", zap.String("id", job.ID), zap.Error(err))
+	}
+
+	fetcher := s.NewFetcher()
+	err = fetcher.Login(ctx)
 	if err != nil {
-		if err2 := stream.Send(Format(fmt.Sprintf("Failed to login to instagram: %s", err.Error()))); err2 != nil {
+		if err2 := send(ErrorFrom(err)); err2 != nil {
 			return err2
 		}
 		logger.Error("Failed to login to Instagram", zap.Error(err))
 		return err
 	}
-	if err = stream.Send(Format("Logged in to instagram")); err != nil {
+	if err = send(LoggedIn()); err != nil {
 		return err
 	}
-	var data string
-	medias := make([]shotstack.Asset, 0)
-	var used float32
-	used = 0
-
-	for _, username := range usernames {
-
-		data, _, err = redis.GetSummarizes(context.Background(), username)
+	usernames := job.Remaining()
+	var ledger budget.Ledger
+	if job.UserID != "" {
+		user, err := users.Get(ctx, job.UserID)
 		if err != nil {
-			logger.Error("Error retrieving summarizes from Redis", zap.String("username", username), zap.Error(err))
+			return err
 		}
-		if err = stream.Send(Format("")); err != nil {
+		if ledger, err = users.NewLedger(user, job.ID); err != nil {
 			return err
 		}
-
-		// Unmarshal data to []string
-		var thisWeek []string
-		err = json.Unmarshal([]byte(data), &thisWeek)
+		// A resumed job may run on another day or after other jobs of the
+		// user, it gets what is left of the quota now
+		used, limit, err := users.Usage(ctx, user)
 		if err != nil {
-			logger.Error("Error unmarshalling data for user", zap.String("username", username), zap.Error(err))
+			return err
 		}
-		logger.Info("Unmarshalled this week's data for user", zap.String("username", username), zap.Strings("thisWeek", thisWeek))
+		job.Left = job.Used + max(limit-used, 0)
+	}
+	spent := budget.New(job.Used, job.Left, ledger)
+	medias := job.Medias
+
+	// Workers send concurrently, a gRPC stream is not safe for that
+	var sendMu sync.Mutex
+	locked := send
+	send = func(event *grpc.SummarizeStoriesResponse) error {
+		sendMu.Lock()
+		defer sendMu.Unlock()
+		return locked(event)
+	}
 
-		// Visit profile
-		if err = stream.Send(Format("Visiting profile")); err != nil {
+	// A daily job fetches every profile first and stops before anything is
+	// summarized or written to history when the stories did not change since
+	// the last digest. Nothing is compared when no profile could be fetched.
+	profiles := make([]*inst2.Profile, len(usernames))
+	prefetched := isDaily && job.Previous != ""
+	if prefetched {
+		err = parallel(ctx, len(usernames), config.Config.ProfileWorkers, func(ctx context.Context, i int) error {
+			profile, err := s.fetchProfile(ctx, fetcher, usernames[i], send)
+			profiles[i] = profile
 			return err
-		}
-		profile, err := inst.VisitProfile(username)
+		})
 		if err != nil {
-			logger.Error("Error visiting profile", zap.String("username", username), zap.Error(err))
-			continue
-		}
-		logger.Info("Visited profile for user", zap.String("username", username), zap.String("profile", profile.User.Username))
-
-		// Getting stories
-		if err = stream.Send(Format("Getting stories")); err != nil {
 			return err
 		}
-		storiess, err := profile.User.Stories()
-		if err != nil {
-			logger.Error("Error fetching stories", zap.String("username", username), zap.Error(err))
-			continue
+		fetched := append([]jobs.Result{}, job.Results...)
+		for i, username := range usernames {
+			fetched = append(fetched, jobs.Result{Username: username, Fingerprint: fingerprint(profiles[i])})
 		}
-		logger.Info("Fetched stories", zap.String("username", username), zap.Any("stories", storiess))
-
-		temp := make([]openai.StoriesType, 0)
-		usedIsMoreThanLeft := false
+		if current := jobs.Fingerprint(fetched); current != "" && current == job.Previous {
+			logger.Info("Stories did not change since the last digest", zap.String("id", job.ID))
+			job.Fingerprint = current
+			job.Unchanged = true
+			return nil
+		}
+	}
 
-		for _, story := range storiess.Reel.Items {
-			if usedIsMoreThanLeft {
-				break
-			}
-			var prompt string
-			var addIt bool
-			var resp string
-			var val string
-			var clip_length int
-			var was_video bool
-			for _, media := range story.Videos {
-				was_video = true
-				if usedIsMoreThanLeft {
-					break
-				}
-				val, addIt, err = redis.GetSummarizes(context.Background(), media.URL)
-				if err != nil {
-					if !profile.User.IsBusiness {
-						prompt = fmt.Sprintf("I have a video from an %s's(use it when want to write about him instead of writing 'user') Instagram story. Your task is to determine if it contains any interesting or relevant information about the person's life or news. If it does, summarize this information in 1 short sentence. If the video content is not related to the person's personal life, not interesting or important activities/news, return following response: 'Nothing interesting'. Give logically connected summarize based on previous storieses(if it is empty- don't say me it is empty, give result only based on video or return empty response):%s.Last 7 days stories: %s. Don't repeat what is already summarized and in old storieses. Additional stories info: events: %s, hashtags: %s, polls: %s, locations: %s, questions: %s, sliders: %s, mentions: %s. Maximum tokens: 75, write it as simple as possible, like people would say, use simple words. Response should be in following json format: {\"description\":string,\"addIt\":bool,\"clip_length\":int}. If you think that this stories should be added to short recap video- addIt true, otherwise false. If addIt is true say what's length in seconds it should be in clip as \"clip_length\"",
-							story.User.Username, temp, data, story.StoryEvents, story.StoryHashtags, story.StoryPolls, story.StoryLocations, story.StorySliders, story.StoryQuestions, story.Mentions)
-					} else {
-						prompt = fmt.Sprintf("I have a video from an %s's(use it when want to write about him instead of writing 'user') Instagram story. Your task is to determine if it contains any interesting or relevant information about the busines's news or sales. If it does, summarize this information in 1 short sentence. If the video content is not interesting or important activities/news, return following response: 'Nothing interesting'. Give logically connected summarize based on previous storieses(if it is empty- don't say me it is empty, give result only based on video or return empty response):%s.Last 7 days stories: %s. Don't repeat what is already summarized and in old storieses. Additional stories info: events: %s, hashtags: %s, polls: %s, locations: %s, questions: %s, sliders: %s, mentions: %s. Maximum tokens: 75, write it as simple as possible, like people would say, use simple words. Response should be in following json format: {\"description\":string,\"addIt\":bool,\"clip_length\":int}. If you think that this stories should be added to short recap video- addIt true, otherwise false . If addIt is true say what's length in seconds it should be in clip as \"clip_length\"",
-							story.User.Username, temp, data, story.StoryEvents, story.StoryHashtags, story.StoryPolls, story.StoryLocations, story.StorySliders, story.StoryQuestions, story.Mentions)
-					}
-
-					resp, clip_length, addIt, err = gemini.SummarizeVideo(media.URL, prompt)
-					used += 1
-					if used >= left {
-						usedIsMoreThanLeft = true
-						break
-					}
-					if err != nil {
-						logger.Error("Error summarizing video", zap.String("URL", media.URL), zap.Error(err))
-						continue
-					}
-					if addIt {
-						var tempAsset shotstack.Asset
-						tempAsset.Type = "video"
-						tempAsset.Src = media.URL
-						tempAsset.Length = clip_length
-						medias = append(medias, tempAsset)
-					}
-					err = redis.StoreSummarizes(context.Background(), media.URL, map[string]interface{}{"value": resp, "addIt": addIt}, "", 24*time.Hour)
-					if err != nil {
-						logger.Error("Error storing summarized video in Redis", zap.String("URL", media.URL), zap.Error(err))
-						continue
-					}
-				} else {
-					resp = val
-				}
-				if resp != "Nothing interesting" || resp != "Nothing interesting." {
-					var tempStoriesType openai.StoriesType
-					tempStoriesType.Author = story.User.Username
-					tempStoriesType.Summarize = resp
-					if profile.User.Friendship.FollowedBy {
-						temp = append([]openai.StoriesType{tempStoriesType}, temp...)
-					} else {
-						temp = append(temp, tempStoriesType)
-					}
-				}
-				if err = stream.Send(Format(resp)); err != nil {
-					return err
-				}
-				break
-			}
-			if was_video {
-				continue
+	// Results are applied to the job in the order of its usernames, whichever finishes first
+	var mu sync.Mutex
+	results := make([]*profileResult, len(usernames))
+	next := 0
+	finish := func(i int, result *profileResult) error {
+		mu.Lock()
+		defer mu.Unlock()
+		results[i] = result
+		for ; next < len(usernames) && results[next] != nil; next++ {
+			result := results[next]
+			job.Results = append(job.Results, jobs.Result{Username: usernames[next], Summary: result.summary, Skipped: result.skipped, SkippedStories: result.skippedStories, Used: result.used, Cached: result.cached, Refunded: result.refunded, Stories: result.stories, Fingerprint: result.fingerprint})
+			job.Used += result.used
+			medias = append(medias, result.medias...)
+			job.Medias = medias
+			if err := s.Jobs.Save(ctx, job); err != nil {
+				logger.Error("Error saving job", zap.String("id", job.ID), zap.Error(err))
 			}
-			for _, media := range story.Images.Versions {
-				val, addIt, err = redis.GetSummarizes(context.Background(), media.URL)
-				if err != nil {
-					if !profile.User.IsBusiness {
-						prompt = fmt.Sprintf("I have an image from an %s's(use it when want to write about him instead of writing 'user') Instagram story. Your task is to determine if it contains any interesting or relevant information about the person's life or news. If it does, summarize this information in 1 short sentence. If the image content is not related to the person's personal life, not interesting or important activities/news, return following response: 'Nothing interesting'. Give logically connected summarize based on previous storieses(if it is empty- don't say me it is empty, give result only based on photo or return empty response):%s.Last 7 days stories: %s. Don't repeat what is already summarized and in old storieses. Additional stories info: events: %s, hashtags: %s, polls: %s, locations: %s, questions: %s, sliders: %s, mentions: %s. Maximum tokens: 75, write it as simple as possible, like people would say, use simple words. Response should be in following json format: {\"description\":string,\"addIt\":bool,\"clip_length\":int}. If you think that this stories should be added to short recap video- addIt true, otherwise false . If addIt is true say what's length in seconds it should be in clip as \"clip_length\"",
-							story.User.Username, temp, data, story.StoryEvents, story.StoryHashtags, story.StoryPolls, story.StoryLocations, story.StorySliders, story.StoryQuestions, story.Mentions)
-					} else {
-						prompt = fmt.Sprintf("I have an image from an %s's(use it when want to write about him instead of writing 'user') Instagram story. Your task is to determine if it contains any interesting or relevant information about the busines's news or sales. If it does, summarize this information in 1 short sentence. If the image content is not interesting or important activities/news, return following response: 'Nothing interesting'. Give logically connected summarize based on previous storieses(if it is empty- don't say me it is empty, give result only based on photo or return empty response):%s.Last 7 days stories: %s. Don't repeat what is already summarized and in old storieses. Additional stories info: events: %s, hashtags: %s, polls: %s, locations: %s, questions: %s, sliders: %s, mentions: %s. Maximum tokens: 75, write it as simple as possible, like people would say, use simple words. Response should be in following json format: {\"description\":string,\"addIt\":bool,\"clip_length\"}. If you think that this stories should be added to short recap video- addIt true, otherwise false. If addIt is true say what's length in seconds it should be in clip as \"clip_length\"",
-							story.User.Username, temp, data, story.StoryEvents, story.StoryHashtags, story.StoryPolls, story.StoryLocations, story.StorySliders, story.StoryQuestions, story.Mentions)
-					}
-					resp, clip_length, addIt, err = openai.SummarizeImage(media.URL, prompt)
-					used += 1
-					if used >= left {
-						usedIsMoreThanLeft = true
-						break
-					}
-					if err != nil {
-						logger.Error("Error summarizing image", zap.String("URL", media.URL), zap.Error(err))
-						continue
-					}
-					if addIt {
-						var tempAsset shotstack.Asset
-						tempAsset.Type = "image"
-						tempAsset.Src = media.URL
-						tempAsset.Length = clip_length
-						medias = append(medias, tempAsset)
-					}
-
-					err = redis.StoreSummarizes(context.Background(), media.URL, map[string]interface{}{"value": resp, "addIt": addIt}, "", 24*time.Hour)
-					if err != nil {
-						logger.Error("Error storing summarized image in Redis", zap.String("URL", media.URL), zap.Error(err))
-						continue
-					}
-
-				} else {
-					resp = val
-				}
-				if resp != "Nothing interesting" || resp != "Nothing interesting." {
-					var tempStoriesType openai.StoriesType
-					tempStoriesType.Author = story.User.Username
-					tempStoriesType.Summarize = resp
-					if profile.User.Friendship.FollowedBy {
-						temp = append([]openai.StoriesType{tempStoriesType}, temp...)
-					} else {
-						temp = append(temp, tempStoriesType)
-					}
-				}
-				if err = stream.Send(Format(resp)); err != nil {
-					return err
-				}
-
-				break
+			if err := send(ProfileFinished(usernames[next], result.summary, result.skipped, result.skippedStories)); err != nil {
+				return err
 			}
+		}
+		return nil
+	}
 
+	err = parallel(ctx, len(usernames), config.Config.ProfileWorkers, func(ctx context.Context, i int) error {
+		profile := profiles[i]
+		if !prefetched {
+			var err error
+			if profile, err = s.fetchProfile(ctx, fetcher, usernames[i], send); err != nil {
+				return err
+			}
 		}
-		summarize, err := openai.SummarizeImagesToOne(temp, profile.User.IsBusiness, preferences)
-		logger.Info(fmt.Sprintf("%s", temp))
+		result, err := s.summarizeProfile(ctx, profile, usernames[i], preferences, spent, send)
 		if err != nil {
-			log.Println("Error summarizing multiple images to one for user:", username, err)
-			continue
+			return err
 		}
-		log.Println("Summarized multiple images to one:", summarize)
+		return finish(i, result)
+	})
+	if err != nil {
+		return err
+	}
+	used := spent.Used()
 
-		if summarize != "Nothing interesting" {
-			today := time.Now().Format("02.01.2006")
+	job.Fingerprint = job.StoriesFingerprint()
 
-			todayExists := false
-			for _, entry := range thisWeek {
-				if inst2.EntryContainsDate(entry, today) {
-					todayExists = true
-					break
-				}
-			}
-			if !todayExists {
-				thisWeek = append(thisWeek, summarize+" "+today)
-				if len(thisWeek) > 7 {
-					thisWeek = thisWeek[len(thisWeek)-7:]
-				}
-				stringified, err := json.Marshal(thisWeek)
-				if err != nil {
-					logger.Info(fmt.Sprintf("Error marshalling this week's data for user:", username, err))
-					stringified = []byte(data)
-				}
-				err = redis.StoreSummarizes(context.Background(), username, nil, string(stringified), 7*24*time.Hour)
-				if err != nil {
-					logger.Info(fmt.Sprintf("Error storing this week's data in Redis for user:", username, err))
-				}
-			}
-			var usersStories openai.StoriesType
-			usersStories.Author = username
-			usersStories.Summarize = summarize
-			storiesArray = append(storiesArray, usersStories)
+	storiesArray := make([]openai.StoriesType, 0)
+	for _, result := range job.Results {
+		if result.Skipped {
+			continue
 		}
+		var usersStories openai.StoriesType
+		usersStories.Author = result.Username
+		usersStories.Summarize = result.Summary
+		storiesArray = append(storiesArray, usersStories)
 	}
 
 	jsoned, err := json.Marshal(storiesArray)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Error marshalling stories array:", storiesArray, err))
+		logger.Error("Error marshalling stories array", zap.Error(err))
 	}
+	job.Result = string(jsoned)
 	if !isDaily {
-		return stream.Send(&grpc.SummarizeStoriesResponse{Result: string(jsoned), LinkToVideo: "", Used: used})
+		return send(Digest(formatted(job), "", used, job.Results))
 	}
-	skip := false
-	Data, err := shotstack.GenerateVideoJson(medias)
+	url, err := s.Renderer.Render(ctx, medias)
 	if err != nil {
-		logger.Error(fmt.Sprintf("Error generating video JSON from medias:", err))
-		skip = true
+		if ctx.Err() != nil {
+			return ctx.Err()
+		}
+		logger.Error("Error rendering video", zap.Error(err))
+		// The digest is still sent, just without the video
+		if err = send(ErrorFrom(err)); err != nil {
+			return err
+		}
 	}
-	logger.Info(fmt.Sprintf("Generated video JSON from medias:", data))
-	var Id string
-	if !skip {
-		Id, err = shotstack.GenerateVideo(Data)
-		if err != nil {
-			logger.Error(fmt.Sprintf("Error generating video ID:", err))
-			skip = true
+	job.LinkToVideo = url
+	return send(Digest(formatted(job), url, used, job.Results))
+}
+
+// profileResult is what summarizing one username produced
+type profileResult struct {
+	summary  string
+	skipped  bool
+	used     float32
+	cached   int
+	refunded float32
+	// skippedStories were summarized but not interesting enough
+	skippedStories int
+	medias         []shotstack.Asset
+	stories        []jobs.Story
+	// fingerprint is empty when the profile could not be fetched
+	fingerprint string
+}
+
+// storyResult is what summarizing one story produced, summary is empty when
+// the story was not summarized or skipped
+type storyResult struct {
+	summary  string
+	author   string
+	asset    *shotstack.Asset
+	used     float32
+	cached   bool
+	refunded float32
+	skipped  bool
+	story    jobs.Story
+}
+
+// fetchProfile fetches the stories of username. Only errors that have to stop
+// the whole job are returned, a profile that failed on its own is nil.
+func (s *Server) fetchProfile(ctx context.Context, fetcher inst2.StoryFetcher, username string, send func(*grpc.SummarizeStoriesResponse) error) (*inst2.Profile, error) {
+	if err := send(ProfileStarted(username)); err != nil {
+		return nil, err
+	}
+	profile, err := fetcher.Fetch(ctx, username)
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		if inst2.IsLoginError(err) {
+			if err2 := send(ErrorFrom(err)); err2 != nil {
+				return nil, err2
+			}
+			logger.Error("Failed to login to Instagram", zap.Error(err))
+			return nil, err
+		}
+		logger.Error("Error fetching profile", zap.String("username", username), zap.Error(err))
+		return nil, nil
+	}
+	logger.Info("Fetched stories", zap.String("username", username), zap.Any("stories", profile.Stories))
+	return profile, nil
+}
+
+// summarizeProfile summarizes the stories of a fetched profile into one
+// summary, a nil profile is skipped. Only errors that have to stop the whole
+// job are returned.
+func (s *Server) summarizeProfile(ctx context.Context, profile *inst2.Profile, username string, preferences string, spent *budget.Budget, send func(*grpc.SummarizeStoriesResponse) error) (*profileResult, error) {
+	result := &profileResult{skipped: true}
+	if profile == nil {
+		return result, nil
+	}
+	result.fingerprint = fingerprint(profile)
+
+	past, err := s.History.Get(ctx, username)
+	if err != nil {
+		logger.Error("Error retrieving history", zap.String("username", username), zap.Error(err))
+		past = &history.History{Username: username}
+	}
+
+	// Stories summarized in parallel do not see summaries of each other, one
+	// worker keeps them in order with the earlier ones as context
+	workers := config.Config.StoryWorkers
+	stories := make([]storyResult, len(profile.Stories))
+	err = parallel(ctx, len(profile.Stories), workers, func(ctx context.Context, i int) error {
+		var previous []openai.StoriesType
+		if workers <= 1 {
+			previous = collect(stories[:i], profile.FollowedBy)
+		}
+		story, err := s.summarizeStory(ctx, profile, profile.Stories[i], previous, past.Context(), spent, send)
+		stories[i] = story
+		return err
+	})
+	for _, story := range stories {
+		result.used += story.used
+		result.refunded += story.refunded
+		if story.cached {
+			result.cached++
+		}
+		if story.skipped {
+			result.skippedStories++
+		}
+		if story.asset != nil {
+			result.medias = append(result.medias, *story.asset)
+		}
+		if story.summary != "" {
+			result.stories = append(result.stories, story.story)
+		}
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	temp := collect(stories, profile.FollowedBy)
+	if len(temp) == 0 {
+		return result, nil
+	}
+	summarize, err := s.Merge.SummarizeToOne(ctx, temp, profile.IsBusiness, preferences)
+	logger.Info(fmt.Sprintf("%s", temp))
+	if err != nil {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		log.Println("Error summarizing multiple images to one for user:", username, err)
+		if !errors.Is(err, resilience.ErrUnavailable) {
+			return result, nil
+		}
+		// Story summaries are already paid for, the profile is kept in the digest without merging them
+		summaries := make([]string, 0, len(temp))
+		for _, story := range temp {
+			summaries = append(summaries, story.Summarize)
+		}
+		summarize = strings.Join(summaries, " ")
+	}
+	log.Println("Summarized multiple images to one:", summarize)
+	result.summary = summarize
+	result.skipped = reply.NothingInteresting(summarize)
+
+	if !result.skipped && past.Add(record(summarize, result.stories)) {
+		if err = s.History.Save(context.Background(), past, users.HistoryDays()); err != nil {
+			logger.Error("Error storing history", zap.String("username", username), zap.Error(err))
+		}
+	}
+	return result, nil
+}
+
+// record is the history record of today made of the summarized stories
+func record(summary string, stories []jobs.Story) history.Record {
+	now := time.Now()
+	r := history.Record{
+		Date:       now.Format(history.DateLayout),
+		Summary:    summary,
+		StoryIDs:   make([]string, 0, len(stories)),
+		MediaTypes: make([]string, 0, len(stories)),
+		Tags:       make([]string, 0),
+		CreatedAt:  now,
+	}
+	seen := make(map[string]bool)
+	for _, story := range stories {
+		r.StoryIDs = append(r.StoryIDs, story.ID)
+		if !seen[story.Type] {
+			seen[story.Type] = true
+			r.MediaTypes = append(r.MediaTypes, story.Type)
+		}
+		for _, tag := range story.Tags {
+			if !seen[tag] {
+				seen[tag] = true
+				r.Tags = append(r.Tags, tag)
+			}
+		}
+	}
+	return r
+}
+
+// summarizeStory summarizes one story from the cache or a provider. Units for
+// a provider call are reserved first and refunded when the call fails. Stories
+// that are not interesting or below the relevance threshold are skipped.
+func (s *Server) summarizeStory(ctx context.Context, profile *inst2.Profile, story inst2.Story, previous []openai.StoriesType, history string, spent *budget.Budget, send func(*grpc.SummarizeStoriesResponse) error) (storyResult, error) {
+	var result storyResult
+	media := story.Media
+	if media.URL == "" {
+		return result, nil
+	}
+
+	var summary reply.Story
+	var hit bool
+	key, err := cache.Key(ctx, media)
+	if err != nil {
+		if ctx.Err() != nil {
+			return result, ctx.Err()
 		}
+		logger.Error("Error building cache key", zap.String("URL", media.URL), zap.Error(err))
+	} else {
+		summary, hit = s.Cache.Get(ctx, key)
 	}
-	logger.Info(fmt.Sprintf("Generated video with ID:", Id))
-	var url string
-	if !skip {
-		url, err = shotstack.GetUrl(Id)
+	result.cached = hit
+	if !hit {
+		prompt, version, err := storyPrompt(story, profile.IsBusiness, previous, history)
+		if err != nil {
+			logger.Error("Error building prompt", zap.String("story", story.ID), zap.Error(err))
+			return result, nil
+		}
+		reservation, err := spent.Reserve(budget.StoryCost)
+		if err != nil {
+			logger.Info("Story is not summarized", zap.String("username", profile.Username), zap.String("story", story.ID), zap.Error(err))
+			return result, nil
+		}
+		if media.Type == inst2.Video {
+			summary, err = s.Videos.SummarizeVideo(ctx, media.URL, prompt)
+		} else {
+			summary, err = s.Images.SummarizeImage(ctx, media.URL, prompt)
+		}
 		if err != nil {
-			logger.Error(fmt.Sprintf("Error generating video URL:", err))
-			skip = true
+			reservation.Refund()
+			result.refunded = reservation.Units()
+			if ctx.Err() != nil {
+				return result, ctx.Err()
+			}
+			logger.Error("Error summarizing "+media.Type, zap.String("URL", media.URL), zap.Error(err))
+			return result, nil
+		}
+		used := reservation.Commit()
+		result.used = reservation.Units()
+		if err = send(UsageDelta(reservation.Units(), used)); err != nil {
+			return result, err
+		}
+		if key != "" {
+			if err = s.Cache.Store(context.Background(), key, summary, version); err != nil {
+				logger.Error("Error storing summarized "+media.Type+" in Redis", zap.String("key", key), zap.Error(err))
+			}
 		}
 	}
-	return stream.Send(&grpc.SummarizeStoriesResponse{Result: string(jsoned), LinkToVideo: url, Used: used})
-	return nil
+	if !summary.Interesting || summary.Relevance < config.Config.RelevanceThreshold || reply.NothingInteresting(summary.Description) {
+		result.skipped = true
+		return result, nil
+	}
+
+	result.summary = summary.Description
+	result.author = story.Username
+	if summary.AddIt {
+		result.asset = &shotstack.Asset{Type: media.Type, Src: media.URL, Length: summary.ClipLength}
+	}
+	result.story = jobs.Story{
+		ID:        story.ID,
+		Type:      media.Type,
+		Link:      fmt.Sprintf("https://www.instagram.com/stories/%s/%s/", profile.Username, story.ID),
+		Thumbnail: media.Thumbnail,
+		TakenAt:   story.TakenAt,
+		Summary:   summary.Description,
+		Tags:      story.Tags(),
+	}
+	if err = send(StorySummary(profile.Username, summary.Description, media.Type, summary.AddIt)); err != nil {
+		return result, err
+	}
+	return result, nil
 }
 
-func Format(text string) *grpc.SummarizeStoriesResponse {
-	return &grpc.SummarizeStoriesResponse{Result: text}
+// collect returns summaries of stories in their order, stories of profiles
+// that follow back are put in front of the others
+func collect(stories []storyResult, followedBy bool) []openai.StoriesType {
+	temp := make([]openai.StoriesType, 0, len(stories))
+	for _, story := range stories {
+		if story.summary == "" {
+			continue
+		}
+		tempStoriesType := openai.StoriesType{Author: story.author, Summarize: story.summary}
+		if followedBy {
+			temp = append([]openai.StoriesType{tempStoriesType}, temp...)
+		} else {
+			temp = append(temp, tempStoriesType)
+		}
+	}
+	return temp
 }
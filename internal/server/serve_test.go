@@ -0,0 +1,393 @@
+package server
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/history"
+	inst2 "github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/scheduler"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
+	"github.com/rendizi/stay-connected-inst/internal/services/shotstack"
+	"github.com/rendizi/stay-connected-inst/internal/services/summarizer"
+	"path/filepath"
+	"strings"
+	"sync"
+	"sync/atomic"
+	"testing"
+)
+
+// Fixture stories the fake summarizer finds relevant enough, the others of
+// alice and bob are below the threshold
+const (
+	aliceKept = "https://cdn.example.com/stories/alice/3.jpg"
+	bobKept1  = "https://cdn.example.com/stories/bob/1.jpg"
+	bobKept2  = "https://cdn.example.com/stories/bob/2.jpg"
+)
+
+func TestRunFixtures(t *testing.T) {
+	defer setConfig(&config.Config.RelevanceThreshold, 0.5)()
+	defer setConfig(&config.Config.ProfileWorkers, 2)()
+	s := newTestServer()
+
+	// carol has no stories and dave no fixture, both are skipped
+	job := jobs.New("job-1", "", []string{"alice", "bob", "carol", "dave"}, 10, false, "news")
+	events, err := runJob(s, context.Background(), job)
+	if err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if job.Status != jobs.Done {
+		t.Errorf("status = %s, want done", job.Status)
+	}
+	if stored := s.Jobs.(*memoryJobs).get(job.ID); stored.Status != jobs.Done {
+		t.Errorf("stored status = %s, want done", stored.Status)
+	}
+
+	want := []jobs.Result{
+		{Username: "alice", Summary: summaryOf(aliceKept), SkippedStories: 2, Used: 3},
+		{Username: "bob", Summary: summaryOf(bobKept1) + " " + summaryOf(bobKept2), SkippedStories: 1, Used: 3},
+		{Username: "carol", Skipped: true},
+		{Username: "dave", Skipped: true},
+	}
+	if len(job.Results) != len(want) {
+		t.Fatalf("got %d results, want %d", len(job.Results), len(want))
+	}
+	for i, w := range want {
+		got := job.Results[i]
+		if got.Username != w.Username || got.Summary != w.Summary || got.Skipped != w.Skipped || got.SkippedStories != w.SkippedStories || got.Used != w.Used {
+			t.Errorf("result %d = %+v, want %+v", i, got, w)
+		}
+	}
+	if job.Used != 6 {
+		t.Errorf("used = %v, want 6", job.Used)
+	}
+	if stories := job.Results[0].Stories; len(stories) != 1 || stories[0].Tags[0] != "#hiking" {
+		t.Errorf("stories of alice = %+v", stories)
+	}
+
+	digest := events[len(events)-1].GetDigest()
+	if digest == nil {
+		t.Fatalf("last event is %v, want the digest", events[len(events)-1])
+	}
+	var summaries []openai.StoriesType
+	if err = json.Unmarshal([]byte(digest.Result), &summaries); err != nil {
+		t.Fatalf("invalid digest result %q: %v", digest.Result, err)
+	}
+	if len(summaries) != 2 || summaries[0].Author != "alice" || summaries[1].Author != "bob" {
+		t.Errorf("digest = %+v, want alice and bob", summaries)
+	}
+	if digest.SkippedStories != 3 {
+		t.Errorf("digest skipped %d stories, want 3", digest.SkippedStories)
+	}
+
+	past := s.History.(*memoryHistory)
+	if h, _ := past.Get(context.Background(), "alice"); len(h.Records) != 1 || h.Records[0].Summary != summaryOf(aliceKept) {
+		t.Errorf("history of alice = %+v", h)
+	}
+	if h, _ := past.Get(context.Background(), "carol"); len(h.Records) != 0 {
+		t.Errorf("history of carol = %+v, want none", h)
+	}
+}
+
+func TestRunServesCachedSummaries(t *testing.T) {
+	defer setConfig(&config.Config.RelevanceThreshold, 0.5)()
+	s := newTestServer()
+
+	first := jobs.New("job-1", "", []string{"alice", "bob"}, 10, false, "news")
+	if _, err := runJob(s, context.Background(), first); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	second := jobs.New("job-2", "", []string{"alice", "bob"}, 10, false, "news")
+	if _, err := runJob(s, context.Background(), second); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if second.Used != 0 {
+		t.Errorf("second job used %v, want everything from the cache", second.Used)
+	}
+	for i, result := range second.Results {
+		if result.Cached != 3 || result.Summary != first.Results[i].Summary {
+			t.Errorf("result %d = %+v, want 3 cached stories and summary %q", i, result, first.Results[i].Summary)
+		}
+	}
+}
+
+func TestRunStopsAtBudget(t *testing.T) {
+	defer setConfig(&config.Config.RelevanceThreshold, 0.5)()
+	defer setConfig(&config.Config.ProfileWorkers, 1)()
+	s := newTestServer()
+
+	// Both units go to the first two stories of alice, which are not relevant
+	job := jobs.New("job-1", "", []string{"alice", "bob"}, 2, false, "news")
+	if _, err := runJob(s, context.Background(), job); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if job.Used != 2 {
+		t.Errorf("used = %v, want 2", job.Used)
+	}
+	for _, result := range job.Results {
+		if !result.Skipped {
+			t.Errorf("result %+v, want skipped", result)
+		}
+	}
+}
+
+func TestRunRefundsFailedCalls(t *testing.T) {
+	defer setConfig(&config.Config.RelevanceThreshold, 0.5)()
+	s := newTestServer()
+	s.Images = failing{Summarizer: summarizer.Fake{}, url: bobKept1}
+
+	job := jobs.New("job-1", "", []string{"bob"}, 10, false, "news")
+	if _, err := runJob(s, context.Background(), job); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	result := job.Results[0]
+	if result.Used != 2 || result.Refunded != 1 || result.Summary != summaryOf(bobKept2) {
+		t.Errorf("result = %+v, want the failed story refunded and left out", result)
+	}
+}
+
+func TestRunCancelled(t *testing.T) {
+	s := newTestServer()
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	job := jobs.New("job-1", "", []string{"alice"}, 10, false, "news")
+	if _, err := runJob(s, ctx, job); !errors.Is(err, context.Canceled) {
+		t.Errorf("run = %v, want context.Canceled", err)
+	}
+	if job.Status != jobs.Cancelled {
+		t.Errorf("status = %s, want cancelled", job.Status)
+	}
+	if s.Queue.Length() != 0 {
+		t.Errorf("queue length = %d after the job stopped", s.Queue.Length())
+	}
+}
+
+func TestRunDailyUnchanged(t *testing.T) {
+	defer setConfig(&config.Config.RelevanceThreshold, 0.5)()
+	s := newTestServer()
+	s.Renderer = video{}
+
+	first := jobs.New("job-1", "", []string{"alice", "bob"}, 10, true, "news")
+	if _, err := runJob(s, context.Background(), first); err != nil {
+		t.Fatalf("first run: %v", err)
+	}
+	if first.Fingerprint == "" || first.Unchanged || first.LinkToVideo == "" {
+		t.Fatalf("first job = %+v, want a fingerprint and a digest", first)
+	}
+
+	// Nothing is summarized or written to history for unchanged stories
+	calls := &counting{Summarizer: summarizer.Fake{}}
+	s.Images, s.Videos, s.Merge = calls, calls, calls
+	s.History = &memoryHistory{histories: make(map[string]history.History)}
+	second := jobs.New("job-2", "", []string{"alice", "bob"}, 10, true, "news")
+	second.Previous = first.Fingerprint
+	if _, err := runJob(s, context.Background(), second); err != nil {
+		t.Fatalf("second run: %v", err)
+	}
+	if !second.Unchanged || second.Status != jobs.Done || second.Result != "" || second.LinkToVideo != "" {
+		t.Errorf("second job = %+v, want done as unchanged without a digest", second)
+	}
+	if second.Fingerprint != first.Fingerprint || len(second.Results) != 0 {
+		t.Errorf("second job = %+v, want the fingerprint of the first without results", second)
+	}
+	if n := calls.n.Load(); n != 0 {
+		t.Errorf("%d summarizer calls for unchanged stories", n)
+	}
+	if h, _ := s.History.Get(context.Background(), "alice"); len(h.Records) != 0 {
+		t.Errorf("history of alice = %+v, want none", h)
+	}
+
+	// Only alice is fetched now, the stories of the digest changed
+	third := jobs.New("job-3", "", []string{"alice", "dave"}, 10, true, "news")
+	third.Previous = first.Fingerprint
+	if _, err := runJob(s, context.Background(), third); err != nil {
+		t.Fatalf("third run: %v", err)
+	}
+	if third.Unchanged || third.Fingerprint == first.Fingerprint {
+		t.Errorf("third job = %+v, want a digest of the changed stories", third)
+	}
+}
+
+func TestRunDailyNothingFetched(t *testing.T) {
+	s := newTestServer()
+	s.Renderer = video{}
+
+	// An empty fingerprint is never compared, a digest is made
+	job := jobs.New("job-1", "", []string{"dave"}, 10, true, "news")
+	if _, err := runJob(s, context.Background(), job); err != nil {
+		t.Fatalf("run: %v", err)
+	}
+	if job.Fingerprint != "" || job.Unchanged || job.Status != jobs.Done {
+		t.Errorf("job = %+v, want done without a fingerprint", job)
+	}
+}
+
+// video renders every digest into the same link
+type video struct{}
+
+func (video) Render(ctx context.Context, medias []shotstack.Asset) (string, error) {
+	return "https://cdn.example.com/digest.mp4", nil
+}
+
+// counting counts the calls made to a summarizer
+type counting struct {
+	summarizer.Summarizer
+	n atomic.Int32
+}
+
+func (c *counting) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	c.n.Add(1)
+	return c.Summarizer.SummarizeImage(ctx, url, prompt)
+}
+
+func (c *counting) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	c.n.Add(1)
+	return c.Summarizer.SummarizeVideo(ctx, url, prompt)
+}
+
+func (c *counting) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	c.n.Add(1)
+	return c.Summarizer.SummarizeToOne(ctx, stories, busines, preferences)
+}
+
+// failing fails to summarize the story at url
+type failing struct {
+	summarizer.Summarizer
+	url string
+}
+
+func (f failing) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	if url == f.url {
+		return reply.Story{}, fmt.Errorf("%w: status 500", resilience.ErrUnavailable)
+	}
+	return f.Summarizer.SummarizeImage(ctx, url, prompt)
+}
+
+func newTestServer() *Server {
+	return &Server{
+		Images: summarizer.Fake{},
+		Videos: summarizer.Fake{},
+		Merge:  summarizer.Fake{},
+		Queue:  scheduler.New(1),
+		NewFetcher: func() inst2.StoryFetcher {
+			return inst2.FixtureFetcher{Dir: filepath.Join("testdata", "fixtures")}
+		},
+		History:  &memoryHistory{histories: make(map[string]history.History)},
+		Cache:    &memoryCache{stories: make(map[string]reply.Story)},
+		Jobs:     &memoryJobs{jobs: make(map[string]jobs.Job)},
+		attached: make(map[string]*attachment),
+	}
+}
+
+// runJob runs job and returns the events it sent
+func runJob(s *Server, ctx context.Context, job *jobs.Job) ([]*grpc.SummarizeStoriesResponse, error) {
+	var mu sync.Mutex
+	var events []*grpc.SummarizeStoriesResponse
+	err := s.run(ctx, job, func(event *grpc.SummarizeStoriesResponse) error {
+		mu.Lock()
+		defer mu.Unlock()
+		events = append(events, event)
+		return nil
+	})
+	return events, err
+}
+
+// summaryOf is what the fake summarizer says about the story at url
+func summaryOf(url string) string {
+	var story reply.Story
+	if strings.HasSuffix(url, ".mp4") {
+		story, _ = summarizer.Fake{}.SummarizeVideo(context.Background(), url, "")
+	} else {
+		story, _ = summarizer.Fake{}.SummarizeImage(context.Background(), url, "")
+	}
+	return story.Description
+}
+
+// setConfig sets a config value for a test and returns a func restoring it
+func setConfig[T any](field *T, value T) func() {
+	old := *field
+	*field = value
+	return func() { *field = old }
+}
+
+type memoryHistory struct {
+	mu        sync.Mutex
+	histories map[string]history.History
+}
+
+func (m *memoryHistory) Get(ctx context.Context, username string) (*history.History, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	h, ok := m.histories[username]
+	if !ok {
+		return &history.History{Version: history.Version, Username: username, Records: []history.Record{}}, nil
+	}
+	h.Records = append([]history.Record{}, h.Records...)
+	return &h, nil
+}
+
+func (m *memoryHistory) Save(ctx context.Context, h *history.History, days int) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.histories[h.Username] = *h
+	return nil
+}
+
+type memoryCache struct {
+	mu      sync.Mutex
+	stories map[string]reply.Story
+}
+
+func (m *memoryCache) Get(ctx context.Context, key string) (reply.Story, bool) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	story, ok := m.stories[key]
+	return story, ok
+}
+
+func (m *memoryCache) Store(ctx context.Context, key string, story reply.Story, prompt string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.stories[key] = story
+	return nil
+}
+
+type memoryJobs struct {
+	mu   sync.Mutex
+	jobs map[string]jobs.Job
+}
+
+func (m *memoryJobs) Get(ctx context.Context, id string) (*jobs.Job, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	job, ok := m.jobs[id]
+	if !ok {
+		return nil, redis.ErrNotExist
+	}
+	return &job, nil
+}
+
+func (m *memoryJobs) Save(ctx context.Context, job *jobs.Job) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.jobs[job.ID] = *job
+	return nil
+}
+
+func (m *memoryJobs) AddToUser(ctx context.Context, job *jobs.Job) error {
+	return nil
+}
+
+func (m *memoryJobs) get(id string) jobs.Job {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	return m.jobs[id]
+}
@@ -0,0 +1,64 @@
+package server
+
+import (
+	"context"
+	"github.com/rendizi/stay-connected-inst/internal/cache"
+	"github.com/rendizi/stay-connected-inst/internal/history"
+	"github.com/rendizi/stay-connected-inst/internal/jobs"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+)
+
+// HistoryStore keeps the history records of usernames that prompts get as context
+type HistoryStore interface {
+	Get(ctx context.Context, username string) (*history.History, error)
+	Save(ctx context.Context, h *history.History, days int) error
+}
+
+// SummaryCache keeps story summaries by the cache key of their media
+type SummaryCache interface {
+	Get(ctx context.Context, key string) (reply.Story, bool)
+	Store(ctx context.Context, key string, story reply.Story, prompt string) error
+}
+
+// JobStore keeps the progress of jobs so they can be resumed and looked up
+type JobStore interface {
+	Get(ctx context.Context, id string) (*jobs.Job, error)
+	Save(ctx context.Context, job *jobs.Job) error
+	AddToUser(ctx context.Context, job *jobs.Job) error
+}
+
+// The stores of a server are in Redis, tests use stores of their own
+
+type redisHistory struct{}
+
+func (redisHistory) Get(ctx context.Context, username string) (*history.History, error) {
+	return history.Get(ctx, username)
+}
+
+func (redisHistory) Save(ctx context.Context, h *history.History, days int) error {
+	return history.Save(ctx, h, days)
+}
+
+type redisCache struct{}
+
+func (redisCache) Get(ctx context.Context, key string) (reply.Story, bool) {
+	return cache.Get(ctx, key)
+}
+
+func (redisCache) Store(ctx context.Context, key string, story reply.Story, prompt string) error {
+	return cache.Store(ctx, key, story, prompt)
+}
+
+type redisJobs struct{}
+
+func (redisJobs) Get(ctx context.Context, id string) (*jobs.Job, error) {
+	return jobs.Get(ctx, id)
+}
+
+func (redisJobs) Save(ctx context.Context, job *jobs.Job) error {
+	return jobs.Save(ctx, job)
+}
+
+func (redisJobs) AddToUser(ctx context.Context, job *jobs.Job) error {
+	return jobs.AddToUser(ctx, job)
+}
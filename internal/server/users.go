@@ -0,0 +1,134 @@
+package server
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"github.com/rendizi/stay-connected-inst/internal/users"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// How many ledger entries GetUsage returns
+const ledgerEntries = 100
+
+// UsersAdmin manages users, their tracked usernames and plans
+type UsersAdmin struct {
+	grpc.UnimplementedUsersAdminServer
+}
+
+func (a *UsersAdmin) PutUser(ctx context.Context, req *grpc.User) (*grpc.User, error) {
+	if req.GetId() == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+	user, err := users.Get(ctx, req.GetId())
+	if errors.Is(err, redis.ErrNotExist) {
+		user = &users.User{ID: req.GetId()}
+	} else if err != nil {
+		logger.Error("Error loading user", zap.String("user", req.GetId()), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to load user")
+	}
+	user.Usernames = req.GetUsernames()
+	user.Preferences = req.GetPreferences()
+	user.Plan = req.GetPlan()
+	user.Daily = req.GetDaily()
+	user.Timezone = req.GetTimezone()
+	user.DigestAt = req.GetDigestAt()
+	user.Channels = channels(user.Channels, req.GetChannels())
+	if err = users.Save(ctx, user); err != nil {
+		if errors.Is(err, users.ErrUnknownPlan) || errors.Is(err, users.ErrInvalidUser) {
+			return nil, status.Error(codes.InvalidArgument, err.Error())
+		}
+		logger.Error("Error saving user", zap.String("user", user.ID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to save user")
+	}
+	return toUser(user), nil
+}
+
+func (a *UsersAdmin) GetUser(ctx context.Context, req *grpc.UserRequest) (*grpc.User, error) {
+	user, err := getUser(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	return toUser(user), nil
+}
+
+func (a *UsersAdmin) GetUsage(ctx context.Context, req *grpc.UserRequest) (*grpc.UserUsageResponse, error) {
+	user, err := getUser(ctx, req.GetId())
+	if err != nil {
+		return nil, err
+	}
+	used, limit, err := users.Usage(ctx, user)
+	if err != nil {
+		logger.Error("Error loading usage of user", zap.String("user", user.ID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to load usage")
+	}
+	entries, err := users.Entries(ctx, user.ID, ledgerEntries)
+	if err != nil {
+		logger.Error("Error loading ledger of user", zap.String("user", user.ID), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to load ledger")
+	}
+	response := &grpc.UserUsageResponse{Used: used, Limit: limit, Entries: make([]*grpc.LedgerEntry, 0, len(entries))}
+	for _, entry := range entries {
+		response.Entries = append(response.Entries, &grpc.LedgerEntry{JobId: entry.JobID, Units: entry.Units, At: entry.At.Unix()})
+	}
+	return response, nil
+}
+
+func getUser(ctx context.Context, id string) (*users.User, error) {
+	if id == "" {
+		return nil, status.Error(codes.InvalidArgument, "user id is required")
+	}
+	user, err := users.Get(ctx, id)
+	if errors.Is(err, redis.ErrNotExist) {
+		return nil, status.Errorf(codes.NotFound, "user %s not found", id)
+	} else if err != nil {
+		logger.Error("Error loading user", zap.String("user", id), zap.Error(err))
+		return nil, status.Error(codes.Internal, "failed to load user")
+	}
+	return user, nil
+}
+
+// channels converts requested channels, a channel sent without a secret keeps
+// the secret it had before
+func channels(previous []users.Channel, requested []*grpc.Channel) []users.Channel {
+	result := make([]users.Channel, 0, len(requested))
+	for _, channel := range requested {
+		secret := channel.GetSecret()
+		if secret == "" {
+			for _, p := range previous {
+				if p.Type == channel.GetType() && p.Target == channel.GetTarget() {
+					secret = p.Secret
+				}
+			}
+		}
+		result = append(result, users.Channel{Type: channel.GetType(), Target: channel.GetTarget(), Secret: secret})
+	}
+	return result
+}
+
+// toChannels leaves secrets out, they are never returned
+func toChannels(channels []users.Channel) []*grpc.Channel {
+	result := make([]*grpc.Channel, 0, len(channels))
+	for _, channel := range channels {
+		result = append(result, &grpc.Channel{Type: channel.Type, Target: channel.Target})
+	}
+	return result
+}
+
+func toUser(user *users.User) *grpc.User {
+	return &grpc.User{
+		Id:          user.ID,
+		Usernames:   user.Usernames,
+		Preferences: user.Preferences,
+		Plan:        user.Plan,
+		Daily:       user.Daily,
+		Timezone:    user.Timezone,
+		DigestAt:    user.DigestAt,
+		Channels:    toChannels(user.Channels),
+		FeedUrl:     FeedURL(user.ID),
+	}
+}
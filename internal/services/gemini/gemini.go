@@ -1,16 +1,17 @@
 package gemini
 
 import (
+	"bytes"
 	"context"
 	"crypto/rand"
-	"encoding/json"
 	"fmt"
 	"github.com/google/generative-ai-go/genai"
 	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
 	"github.com/rendizi/stay-connected-inst/pkg/logger"
 	"google.golang.org/api/option"
 	"io"
-	"log"
 	"net/http"
 	"strings"
 	"time"
@@ -43,7 +44,7 @@ func getFileURIFromURL(fileURL string) (string, error) {
 	return fileURL, nil
 }
 
-func downloadFile(url string) (io.Reader, string, error) {
+func downloadFile(ctx context.Context, url string) (io.Reader, string, error) {
 	// Generate a random name for the file
 	randomName, err := generateRandomString(30)
 	if err != nil {
@@ -51,11 +52,16 @@ func downloadFile(url string) (io.Reader, string, error) {
 	}
 
 	// Download the file
-	resp, err := http.Get(url)
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, url, nil)
+	if err != nil {
+		return nil, "", fmt.Errorf("failed to create request: %w", err)
+	}
+	resp, err := http.DefaultClient.Do(req)
 	if err != nil {
 		return nil, "", fmt.Errorf("failed to download file: %w", err)
 	}
 	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
 		return nil, "", fmt.Errorf("failed to download file, status code: %d", resp.StatusCode)
 	}
 
@@ -63,20 +69,19 @@ func downloadFile(url string) (io.Reader, string, error) {
 	return resp.Body, randomName, nil
 }
 
-func SummarizeVideo(fileURL string, promptText string) (string, int, bool, error) {
-	ctx := context.Background()
+func SummarizeVideo(ctx context.Context, fileURL string, promptText string) (reply.Story, error) {
 	client, err := genai.NewClient(ctx, option.WithAPIKey(config.Config.GeminiKey))
 	if err != nil {
-		return "", 0, false, err
+		return reply.Story{}, err
 	}
 	defer client.Close()
 
 	model := client.GenerativeModel("gemini-1.5-flash")
 
 	// Download the file from the URL
-	reader, fileName, err := downloadFile(fileURL)
+	reader, fileName, err := downloadFile(ctx, fileURL)
 	if err != nil {
-		return "", 0, false, fmt.Errorf("failed to download file: %w", err)
+		return reply.Story{}, fmt.Errorf("failed to download file: %w", err)
 	}
 	defer func() {
 		if closer, ok := reader.(io.Closer); ok {
@@ -89,25 +94,39 @@ func SummarizeVideo(fileURL string, promptText string) (string, int, bool, error
 	sanitizedFileName = strings.Trim(sanitizedFileName, "-")
 	logger.Info(sanitizedFileName)
 
-	// Upload the file
-	uploadedFile, err := client.UploadFile(ctx, sanitizedFileName, reader, &genai.UploadFileOptions{
-		MIMEType: "video/mp4",
+	// Upload the file, kept in memory so a failed upload can be retried
+	video, err := io.ReadAll(reader)
+	if err != nil {
+		return reply.Story{}, fmt.Errorf("failed to read file: %w", err)
+	}
+	var uploadedFile *genai.File
+	err = resilience.Do(ctx, "gemini", func(ctx context.Context) error {
+		var err error
+		uploadedFile, err = client.UploadFile(ctx, sanitizedFileName, bytes.NewReader(video), &genai.UploadFileOptions{
+			MIMEType: "video/mp4",
+		})
+		return err
 	})
 	if err != nil {
-		return "", 0, false, fmt.Errorf("failed to upload file: %w", err)
+		return reply.Story{}, fmt.Errorf("failed to upload file: %w", err)
 	}
 
 	// Check the file processing state
 	for uploadedFile.State == genai.FileStateProcessing {
-		time.Sleep(5 * time.Second)
+		select {
+		case <-ctx.Done():
+			client.DeleteFile(context.Background(), uploadedFile.Name)
+			return reply.Story{}, ctx.Err()
+		case <-time.After(5 * time.Second):
+		}
 		uploadedFile, err = client.GetFile(ctx, uploadedFile.Name)
 		if err != nil {
-			return "", 0, false, fmt.Errorf("failed to get file status: %w", err)
+			return reply.Story{}, fmt.Errorf("failed to get file status: %w", err)
 		}
 	}
 
 	if uploadedFile.State != genai.FileStateActive {
-		return "", 0, false, fmt.Errorf("uploaded file has state %s, not active", uploadedFile.State)
+		return reply.Story{}, fmt.Errorf("uploaded file has state %s, not active", uploadedFile.State)
 	}
 
 	// Create the prompt
@@ -118,27 +137,113 @@ func SummarizeVideo(fileURL string, promptText string) (string, int, bool, error
 		genai.Text(promptText),
 	}
 
-	resp, err := model.GenerateContent(ctx, prompt...)
+	resp, err := generate(ctx, model, prompt...)
+	if err != nil {
+		return reply.Story{}, err
+	}
+
+	text, err := replyText(resp)
+	if err != nil {
+		return reply.Story{}, err
+	}
+	return reply.DecodeStory(ctx, text, repairWith(model))
+}
+
+func SummarizeImage(ctx context.Context, fileURL string, promptText string) (reply.Story, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(config.Config.GeminiKey))
+	if err != nil {
+		return reply.Story{}, err
+	}
+	defer client.Close()
+
+	model := client.GenerativeModel("gemini-1.5-flash")
+
+	// Download the image, small enough to be sent inline
+	reader, _, err := downloadFile(ctx, fileURL)
 	if err != nil {
-		return "", 0, false, fmt.Errorf("failed to generate content: %w", err)
+		return reply.Story{}, fmt.Errorf("failed to download file: %w", err)
+	}
+	defer func() {
+		if closer, ok := reader.(io.Closer); ok {
+			closer.Close()
+		}
+	}()
+	image, err := io.ReadAll(reader)
+	if err != nil {
+		return reply.Story{}, fmt.Errorf("failed to read file: %w", err)
 	}
 
-	// Collect summary content
+	resp, err := generate(ctx, model, genai.ImageData("jpeg", image), genai.Text(promptText))
+	if err != nil {
+		return reply.Story{}, err
+	}
+
+	text, err := replyText(resp)
+	if err != nil {
+		return reply.Story{}, err
+	}
+	return reply.DecodeStory(ctx, text, repairWith(model))
+}
+
+func SummarizeText(ctx context.Context, systemText string, promptText string) (string, error) {
+	client, err := genai.NewClient(ctx, option.WithAPIKey(config.Config.GeminiKey))
+	if err != nil {
+		return "", err
+	}
+	defer client.Close()
+
+	model := client.GenerativeModel("gemini-1.5-flash")
+	model.SystemInstruction = genai.NewUserContent(genai.Text(systemText))
+	model.SetMaxOutputTokens(100)
+
+	resp, err := generate(ctx, model, genai.Text(promptText))
+	if err != nil {
+		return "", err
+	}
+
+	text, err := replyText(resp)
+	if err != nil {
+		return "", err
+	}
+	return strings.TrimSpace(text), nil
+}
+
+// replyText returns the text of the first candidate that has one
+func replyText(resp *genai.GenerateContentResponse) (string, error) {
 	for _, c := range resp.Candidates {
-		if c.Content != nil {
-			var data map[string]interface{}
-			err = json.Unmarshal([]byte(fmt.Sprintf("%s\n", c.Content.Parts[0])), &data)
-			if err != nil {
-				return "", 0, false, err
+		if c.Content == nil {
+			continue
+		}
+		for _, part := range c.Content.Parts {
+			if text, ok := part.(genai.Text); ok {
+				return string(text), nil
 			}
-			description := data["description"].(string)
-			addIt := data["addIt"].(bool)
-			length := data["clip_length"].(float64)
-			log.Println(data)
+		}
+	}
+	return "", fmt.Errorf("no candidates found in the response")
+}
 
-			return description, int(length), addIt, nil
+// repairWith sends the repair prompt of an invalid reply to model, without the media
+func repairWith(model *genai.GenerativeModel) reply.Complete {
+	return func(ctx context.Context, prompt string) (string, error) {
+		resp, err := generate(ctx, model, genai.Text(prompt))
+		if err != nil {
+			return "", err
 		}
+		return replyText(resp)
 	}
+}
 
-	return "", 0, false, nil
+// generate calls the model, rate limits and server errors are retried
+func generate(ctx context.Context, model *genai.GenerativeModel, parts ...genai.Part) (*genai.GenerateContentResponse, error) {
+	var resp *genai.GenerateContentResponse
+	err := resilience.Do(ctx, "gemini", func(ctx context.Context) error {
+		var err error
+		resp, err = model.GenerateContent(ctx, parts...)
+		return err
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate content: %w", err)
+	}
+	return resp, nil
 }
@@ -1,91 +1,123 @@
 package openai
 
 import (
+	"context"
 	"encoding/json"
 	"fmt"
 	"github.com/go-resty/resty/v2"
+	"github.com/rendizi/stay-connected-inst/internal/prompts"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
 	"github.com/rendizi/stay-connected-inst/pkg/logger"
-	"log"
 	"os"
 )
 
-func SummarizeImage(url string, prompt string) (string, int, bool, error) {
-	apiEndpoint := "https://api.openai.com/v1/chat/completions"
-
-	apiKey := os.Getenv("OPENAI_KEY")
-	client := resty.New()
-
-	response, err := client.R().
-		SetAuthToken(apiKey).
-		SetHeader("Content-Type", "application/json").
-		SetBody(map[string]interface{}{
-			"model": "gpt-4o",
-			"response_format": map[string]interface{}{
-				"type": "json_object",
-			},
-			"messages": []interface{}{
-				map[string]interface{}{
-					"role": "user",
-					"content": []interface{}{
-						map[string]interface{}{
-							"type": "text",
-							"text": prompt,
-						},
-						map[string]interface{}{
-							"type": "image_url",
-							"image_url": map[string]interface{}{
-								"url": url,
-							},
+const apiEndpoint = "https://api.openai.com/v1/chat/completions"
+
+// storyTokens leaves room for every field of a story reply
+const storyTokens = 300
+
+func SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	message, err := chat(ctx, map[string]interface{}{
+		"model": "gpt-4o",
+		"response_format": map[string]interface{}{
+			"type": "json_object",
+		},
+		"messages": []interface{}{
+			map[string]interface{}{
+				"role": "user",
+				"content": []interface{}{
+					map[string]interface{}{
+						"type": "text",
+						"text": prompt,
+					},
+					map[string]interface{}{
+						"type": "image_url",
+						"image_url": map[string]interface{}{
+							"url": url,
 						},
 					},
 				},
 			},
-			"max_tokens": 75,
-		}).
-		Post(apiEndpoint)
-
+		},
+		"max_tokens": storyTokens,
+	})
 	if err != nil {
-		return "", 0, false, fmt.Errorf("Error while sending the request: %v", err)
+		return reply.Story{}, err
 	}
+	return reply.DecodeStory(ctx, message, repair)
+}
 
-	body := response.Body()
-
-	var data map[string]interface{}
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		return "", 0, false, fmt.Errorf("Error while decoding JSON response: %v", err)
-	}
+// repair sends the repair prompt of an invalid reply, it does not need the image again
+func repair(ctx context.Context, prompt string) (string, error) {
+	return chat(ctx, map[string]interface{}{
+		"model": "gpt-4o",
+		"response_format": map[string]interface{}{
+			"type": "json_object",
+		},
+		"messages": []interface{}{
+			map[string]interface{}{
+				"role":    "user",
+				"content": prompt,
+			},
+		},
+		"max_tokens": storyTokens,
+	})
+}
 
-	choices := data["choices"].([]interface{})
-	if len(choices) == 0 {
-		return "", 0, false, fmt.Errorf("No choices found in the response")
-	}
+type completion struct {
+	Choices []struct {
+		Message struct {
+			Content string `json:"content"`
+		} `json:"message"`
+	} `json:"choices"`
+	Error *struct {
+		Message string `json:"message"`
+	} `json:"error"`
+}
 
-	message := choices[0].(map[string]interface{})["message"].(map[string]interface{})["content"].(string)
+// chat sends a chat completion request and returns content of the first choice.
+// Rate limits and server errors are retried.
+func chat(ctx context.Context, body map[string]interface{}) (string, error) {
+	apiKey := os.Getenv("OPENAI_KEY")
+	client := resty.New()
 
-	var result map[string]interface{}
-	err = json.Unmarshal([]byte(message), &result)
+	var response *resty.Response
+	err := resilience.Do(ctx, "openai", func(ctx context.Context) error {
+		var err error
+		response, err = client.R().
+			SetContext(ctx).
+			SetAuthToken(apiKey).
+			SetHeader("Content-Type", "application/json").
+			SetBody(body).
+			Post(apiEndpoint)
+		if err != nil {
+			return fmt.Errorf("Error while sending the request: %w", err)
+		}
+		return resilience.FromResponse(response.StatusCode(), response.Header(), response.String())
+	})
 	if err != nil {
-		return "", 0, false, fmt.Errorf("Error while decoding message content JSON: %v", err)
+		return "", err
 	}
 
-	description, ok := result["description"].(string)
-	if !ok {
-		return "", 0, false, fmt.Errorf("No description found in the response")
+	var data completion
+	err = json.Unmarshal(response.Body(), &data)
+	if err != nil {
+		return "", fmt.Errorf("Error while decoding JSON response: %v", err)
 	}
-
-	length, ok := result["clip_length"].(float64)
-	if !ok {
-		length = 0
+	if data.Error != nil {
+		return "", fmt.Errorf("OpenAI error: %s", data.Error.Message)
 	}
-
-	addIt, ok := result["addIt"].(bool)
-	if !ok {
-		addIt = false
+	if len(data.Choices) == 0 {
+		return "", fmt.Errorf("No choices found in the response")
 	}
-	log.Println(result)
+	return data.Choices[0].Message.Content, nil
+}
 
-	return description, int(length), addIt, nil
+// MergePrompt returns the system prompt used to merge story summarizes into one
+func MergePrompt(busines bool, preferences string) (string, error) {
+	prompt, _, err := prompts.Render(prompts.MergePrompt, busines, prompts.Merge{Preferences: preferences})
+	return prompt, err
 }
 
 type StoriesType struct {
@@ -93,47 +125,24 @@ type StoriesType struct {
 	Summarize string
 }
 
-func SummarizeImagesToOne(userPrompt []StoriesType, busines bool, preferences string) (string, error) {
-	apiKey := os.Getenv("OPENAI_KEY")
-	apiEndpoint := "https://api.openai.com/v1/chat/completions"
-
-	client := resty.New()
-	content := "You are given array of storieses summarize. I am very busy so give the most interesting ones, make them shorter without losing an idea. Maximum symbols-100, don't use markup symbols. Response should be like 1 text, no need to divide into ordered/unordered list. If is is empty or there is information not interesting and not related with someone's life- return 'Nothing interesting'. Write simple. User's preferences: " + preferences
-	if busines {
-		content = "You are given array of storieses summarize of some busines account. I am very buse so give the most interesting ones, make them shorter without losing an idea. Maximum symbols-100, dont use markup symbols. Response should be like 1 text, no need to divide into ordered/unordered list. If it is epty or there is no interestings inferomation, news or info that can be helpful for concurents - return 'Nothing interesting'. Wrtie simple. User's preferences: " + preferences
+func SummarizeImagesToOne(ctx context.Context, userPrompt []StoriesType, busines bool, preferences string) (string, error) {
+	content, err := MergePrompt(busines, preferences)
+	if err != nil {
+		return "", err
 	}
 	logger.Info(content)
 
-	response, err := client.R().
-		SetAuthToken(apiKey).
-		SetHeader("Content-Type", "application/json").
-		SetBody(map[string]interface{}{
-			"model": "gpt-4o",
-			"messages": []interface{}{
-				map[string]interface{}{
-					"role":    "system",
-					"content": content},
-				map[string]interface{}{
-					"role":    "user",
-					"content": fmt.Sprintf("%s", userPrompt),
-				},
+	return chat(ctx, map[string]interface{}{
+		"model": "gpt-4o",
+		"messages": []interface{}{
+			map[string]interface{}{
+				"role":    "system",
+				"content": content},
+			map[string]interface{}{
+				"role":    "user",
+				"content": fmt.Sprintf("%s", userPrompt),
 			},
-			"max_tokens": 100,
-		}).
-		Post(apiEndpoint)
-
-	if err != nil {
-		return "", fmt.Errorf("Error while sending send the request: %v", err)
-	}
-
-	body := response.Body()
-
-	var data map[string]interface{}
-	err = json.Unmarshal(body, &data)
-	if err != nil {
-		return "", fmt.Errorf("Error while decoding JSON response:", err)
-	}
-
-	content = data["choices"].([]interface{})[0].(map[string]interface{})["message"].(map[string]interface{})["content"].(string)
-	return content, nil
+		},
+		"max_tokens": 100,
+	})
 }
@@ -0,0 +1,174 @@
+package reply
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"math"
+	"strings"
+)
+
+var ErrInvalid = errors.New("invalid model reply")
+
+// InvalidError is returned when a reply does not match its schema, also after repair
+type InvalidError struct {
+	Reply  string
+	Reason string
+}
+
+func (e *InvalidError) Error() string {
+	return fmt.Sprintf("%v: %s", ErrInvalid, e.Reason)
+}
+
+func (e *InvalidError) Is(target error) bool {
+	return target == ErrInvalid
+}
+
+// Field types follow JSON schema: string, boolean, integer, number. Numbers
+// are checked to be within Min and Max when Max is set.
+type Field struct {
+	Name     string
+	Type     string
+	Required bool
+	Min      float64
+	Max      float64
+}
+
+type Schema []Field
+
+// StorySchema is the reply of image and video summarizers
+var StorySchema = Schema{
+	{Name: "description", Type: "string", Required: true},
+	{Name: "interesting", Type: "boolean", Required: true},
+	{Name: "relevance", Type: "number", Required: true, Min: 0, Max: 1},
+	{Name: "addIt", Type: "boolean", Required: true},
+	{Name: "clip_length", Type: "integer"},
+}
+
+// Story is a decoded reply of an image or video summarizer. Relevance is from
+// 0 to 1, ClipLength is in seconds and AddIt tells whether the media should be
+// added to the recap video.
+type Story struct {
+	Description string
+	Interesting bool
+	Relevance   float64
+	AddIt       bool
+	ClipLength  int
+}
+
+func (s Schema) String() string {
+	fields := make([]string, 0, len(s))
+	for _, field := range s {
+		fields = append(fields, fmt.Sprintf("%q:%s", field.Name, field.Type))
+	}
+	return "{" + strings.Join(fields, ",") + "}"
+}
+
+// Validate checks data has every required field of the schema and fields of the right type
+func (s Schema) Validate(data map[string]interface{}) error {
+	for _, field := range s {
+		value, ok := data[field.Name]
+		if !ok || value == nil {
+			if field.Required {
+				return fmt.Errorf("missing %q", field.Name)
+			}
+			continue
+		}
+		valid := false
+		switch field.Type {
+		case "string":
+			_, valid = value.(string)
+		case "boolean":
+			_, valid = value.(bool)
+		case "number":
+			_, valid = value.(float64)
+		case "integer":
+			number, ok := value.(float64)
+			valid = ok && number == math.Trunc(number)
+		}
+		if !valid {
+			return fmt.Errorf("%q is not %s", field.Name, field.Type)
+		}
+		if number, ok := value.(float64); ok && field.Max > field.Min && (number < field.Min || number > field.Max) {
+			return fmt.Errorf("%q is not between %v and %v", field.Name, field.Min, field.Max)
+		}
+	}
+	return nil
+}
+
+// Decode strips markdown code fences around text and validates the JSON object in it
+func Decode(text string, schema Schema) (map[string]interface{}, error) {
+	text = stripFences(text)
+	var data map[string]interface{}
+	if err := json.Unmarshal([]byte(text), &data); err != nil {
+		return nil, &InvalidError{Reply: text, Reason: err.Error()}
+	}
+	if err := schema.Validate(data); err != nil {
+		return nil, &InvalidError{Reply: text, Reason: err.Error()}
+	}
+	return data, nil
+}
+
+func stripFences(text string) string {
+	text = strings.TrimSpace(text)
+	if !strings.HasPrefix(text, "```") {
+		return text
+	}
+	text = strings.TrimPrefix(text, "```")
+	// Language of the fence, e.g. ```json
+	if i := strings.IndexByte(text, '\n'); i >= 0 {
+		text = text[i+1:]
+	}
+	text = strings.TrimSuffix(strings.TrimSpace(text), "```")
+	return strings.TrimSpace(text)
+}
+
+// Complete sends a text only prompt to the model and returns its reply
+type Complete func(ctx context.Context, prompt string) (string, error)
+
+// RepairPrompt asks the model to turn an invalid reply into one matching schema
+func RepairPrompt(text string, reason string, schema Schema) string {
+	return fmt.Sprintf("Your previous reply is not valid (%s). Rewrite it as a single JSON object in the format %s, without markdown or any other text. Previous reply: %s", reason, schema, text)
+}
+
+// DecodeOrRepair decodes text. When it is invalid the model is asked once to
+// repair it, an *InvalidError is returned when the repaired reply is invalid too.
+func DecodeOrRepair(ctx context.Context, text string, schema Schema, complete Complete) (map[string]interface{}, error) {
+	data, err := Decode(text, schema)
+	var invalid *InvalidError
+	if !errors.As(err, &invalid) {
+		return data, err
+	}
+	repaired, err := complete(ctx, RepairPrompt(invalid.Reply, invalid.Reason, schema))
+	if err != nil {
+		return nil, fmt.Errorf("failed to repair reply: %w", err)
+	}
+	return Decode(repaired, schema)
+}
+
+// NothingInteresting tells whether text is the reply of a prompt asked to return
+// 'Nothing interesting' when there is nothing to summarize
+func NothingInteresting(text string) bool {
+	text = strings.TrimSuffix(strings.TrimSpace(text), ".")
+	return text == "" || strings.EqualFold(text, "Nothing interesting")
+}
+
+// DecodeStory decodes the reply of an image or video summarizer
+func DecodeStory(ctx context.Context, text string, complete Complete) (Story, error) {
+	data, err := DecodeOrRepair(ctx, text, StorySchema, complete)
+	if err != nil {
+		return Story{}, err
+	}
+	length, _ := data["clip_length"].(float64)
+	if length < 0 {
+		length = 0
+	}
+	return Story{
+		Description: data["description"].(string),
+		Interesting: data["interesting"].(bool),
+		Relevance:   data["relevance"].(float64),
+		AddIt:       data["addIt"].(bool),
+		ClipLength:  int(length),
+	}, nil
+}
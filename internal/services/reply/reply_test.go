@@ -0,0 +1,134 @@
+package reply
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+const valid = `{"description":"Went hiking","interesting":true,"relevance":0.8,"addIt":true,"clip_length":4}`
+
+func TestDecode(t *testing.T) {
+	tests := []struct {
+		name   string
+		text   string
+		reason string
+	}{
+		{"unfenced", valid, ""},
+		{"fenced", "```json\n" + valid + "\n```", ""},
+		{"fenced without language", "```\n" + valid + "\n```", ""},
+		{"surrounding space", "\n  " + valid + "  \n", ""},
+		{"optional field missing", `{"description":"a","interesting":false,"relevance":0,"addIt":false}`, ""},
+		{"not json", "Sure! Here is the summary", "invalid character"},
+		{"missing required field", `{"description":"a","interesting":true,"addIt":true}`, `missing "relevance"`},
+		{"null required field", `{"description":null,"interesting":true,"relevance":0.5,"addIt":true}`, `missing "description"`},
+		{"wrong type", `{"description":"a","interesting":"yes","relevance":0.5,"addIt":true}`, `"interesting" is not boolean`},
+		{"fractional integer", `{"description":"a","interesting":true,"relevance":0.5,"addIt":true,"clip_length":2.5}`, `"clip_length" is not integer`},
+		{"relevance above range", `{"description":"a","interesting":true,"relevance":1.5,"addIt":true}`, `"relevance" is not between 0 and 1`},
+		{"relevance below range", `{"description":"a","interesting":true,"relevance":-0.1,"addIt":true}`, `"relevance" is not between 0 and 1`},
+	}
+	for _, tt := range tests {
+		data, err := Decode(tt.text, StorySchema)
+		if tt.reason == "" {
+			if err != nil {
+				t.Errorf("%s: Decode = %v", tt.name, err)
+			} else if data["description"] == nil {
+				t.Errorf("%s: Decode = %v, want the description", tt.name, data)
+			}
+			continue
+		}
+		var invalid *InvalidError
+		if !errors.As(err, &invalid) || !errors.Is(err, ErrInvalid) {
+			t.Errorf("%s: Decode = %v, want an *InvalidError", tt.name, err)
+			continue
+		}
+		if !strings.Contains(invalid.Reason, tt.reason) {
+			t.Errorf("%s: reason = %q, want %q", tt.name, invalid.Reason, tt.reason)
+		}
+	}
+}
+
+// repairs answers repair prompts with replies in order and keeps the prompts
+type repairs struct {
+	replies []string
+	prompts []string
+	err     error
+}
+
+func (r *repairs) complete(ctx context.Context, prompt string) (string, error) {
+	r.prompts = append(r.prompts, prompt)
+	if r.err != nil {
+		return "", r.err
+	}
+	reply := r.replies[0]
+	r.replies = r.replies[1:]
+	return reply, nil
+}
+
+func TestDecodeStory(t *testing.T) {
+	r := &repairs{}
+	story, err := DecodeStory(context.Background(), "```json\n"+valid+"\n```", r.complete)
+	if err != nil {
+		t.Fatalf("DecodeStory: %v", err)
+	}
+	want := Story{Description: "Went hiking", Interesting: true, Relevance: 0.8, AddIt: true, ClipLength: 4}
+	if story != want {
+		t.Errorf("story = %+v, want %+v", story, want)
+	}
+	if len(r.prompts) != 0 {
+		t.Errorf("valid reply was repaired %d times", len(r.prompts))
+	}
+}
+
+func TestDecodeOrRepairRepairsOnce(t *testing.T) {
+	r := &repairs{replies: []string{valid}}
+	invalid := `{"description":"Went hiking","interesting":"yes"}`
+	story, err := DecodeStory(context.Background(), invalid, r.complete)
+	if err != nil {
+		t.Fatalf("DecodeStory: %v", err)
+	}
+	if story.Description != "Went hiking" {
+		t.Errorf("story = %+v", story)
+	}
+	if len(r.prompts) != 1 {
+		t.Fatalf("repaired %d times, want once", len(r.prompts))
+	}
+	if !strings.Contains(r.prompts[0], invalid) || !strings.Contains(r.prompts[0], `"interesting" is not boolean`) {
+		t.Errorf("repair prompt %q misses the reply or the reason", r.prompts[0])
+	}
+}
+
+func TestDecodeOrRepairFails(t *testing.T) {
+	r := &repairs{replies: []string{"still not json", valid}}
+	_, err := DecodeStory(context.Background(), "not json", r.complete)
+	var invalid *InvalidError
+	if !errors.As(err, &invalid) || invalid.Reply != "still not json" {
+		t.Errorf("DecodeStory = %v, want an *InvalidError of the repaired reply", err)
+	}
+	if len(r.prompts) != 1 {
+		t.Errorf("repaired %d times, want once", len(r.prompts))
+	}
+
+	// A failed repair call is not an invalid reply
+	unavailable := errors.New("provider unavailable")
+	r = &repairs{err: unavailable}
+	_, err = DecodeStory(context.Background(), "not json", r.complete)
+	if !errors.Is(err, unavailable) || errors.Is(err, ErrInvalid) {
+		t.Errorf("DecodeStory = %v, want the error of the repair call", err)
+	}
+}
+
+func TestNothingInteresting(t *testing.T) {
+	for text, want := range map[string]bool{
+		"":                      true,
+		"Nothing interesting":   true,
+		"nothing interesting.":  true,
+		" Nothing interesting ": true,
+		"Went hiking":           false,
+	} {
+		if got := NothingInteresting(text); got != want {
+			t.Errorf("NothingInteresting(%q) = %v, want %v", text, got, want)
+		}
+	}
+}
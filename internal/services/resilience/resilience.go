@@ -0,0 +1,245 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+	"google.golang.org/api/googleapi"
+	"math/rand"
+	"net"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+)
+
+// ErrUnavailable is returned when a provider kept failing or its circuit is
+// open, callers may fail over to another provider
+var ErrUnavailable = errors.New("provider unavailable")
+
+var ErrCircuitOpen = errors.New("circuit open")
+
+// StatusError is an unsuccessful HTTP response of a provider
+type StatusError struct {
+	StatusCode int
+	RetryAfter time.Duration
+	Message    string
+}
+
+func (e *StatusError) Error() string {
+	return fmt.Sprintf("status %d: %s", e.StatusCode, e.Message)
+}
+
+// FromResponse returns a *StatusError for a non 2xx response, nil otherwise
+func FromResponse(statusCode int, header http.Header, body string) error {
+	if statusCode >= 200 && statusCode < 300 {
+		return nil
+	}
+	return &StatusError{StatusCode: statusCode, RetryAfter: retryAfter(header), Message: body}
+}
+
+// retryAfter parses the Retry-After header, given in seconds or as an HTTP date
+func retryAfter(header http.Header) time.Duration {
+	value := header.Get("Retry-After")
+	if value == "" {
+		return 0
+	}
+	if seconds, err := strconv.Atoi(value); err == nil && seconds > 0 {
+		return time.Duration(seconds) * time.Second
+	}
+	if at, err := http.ParseTime(value); err == nil {
+		return time.Until(at)
+	}
+	return 0
+}
+
+// Do calls fn until it succeeds, fails with an error that is not worth
+// retrying or runs out of attempts. Every attempt gets the timeout of the
+// provider, attempts wait with exponential backoff and jitter or as long as
+// Retry-After says. Transient failures count towards the circuit breaker of
+// the provider, no attempt is made while it is open.
+func Do(ctx context.Context, provider string, fn func(ctx context.Context) error) error {
+	b := breakerOf(provider)
+	attempts := config.Config.RetryAttempts
+	if attempts < 1 {
+		attempts = 1
+	}
+	var err error
+	for attempt := 0; attempt < attempts; attempt++ {
+		if !b.allow(time.Now()) {
+			return fmt.Errorf("%s: %w: %w", provider, ErrUnavailable, ErrCircuitOpen)
+		}
+		err = call(ctx, provider, fn)
+		if err != nil && ctx.Err() != nil {
+			// Cancelled by the caller, says nothing about the provider
+			b.abort()
+			return err
+		}
+		if err == nil || !transient(ctx, err) {
+			b.success()
+			return err
+		}
+		if b.failure(time.Now()) {
+			logger.Error("Circuit opened", zap.String("provider", provider), zap.Error(err))
+		}
+		if attempt == attempts-1 {
+			break
+		}
+		wait := backoff(attempt)
+		var status *StatusError
+		if errors.As(err, &status) && status.RetryAfter > 0 {
+			wait = status.RetryAfter
+		}
+		var gerr *googleapi.Error
+		if errors.As(err, &gerr) && retryAfter(gerr.Header) > 0 {
+			wait = retryAfter(gerr.Header)
+		}
+		// A provider asking to wait longer than the longest backoff is treated
+		// as unavailable rather than holding the job
+		if wait > config.Config.RetryMaxBackoff {
+			break
+		}
+		logger.Info("Retrying provider call", zap.String("provider", provider), zap.Int("attempt", attempt+1), zap.Duration("wait", wait), zap.Error(err))
+		if err2 := sleep(ctx, wait); err2 != nil {
+			return err2
+		}
+	}
+	return fmt.Errorf("%s: %w: %w", provider, ErrUnavailable, err)
+}
+
+func call(ctx context.Context, provider string, fn func(ctx context.Context) error) error {
+	if timeout := Timeout(provider); timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return fn(ctx)
+}
+
+// Timeout returns how long a single call to the provider may take, 0 is unlimited
+func Timeout(provider string) time.Duration {
+	switch provider {
+	case "openai":
+		return config.Config.OpenAITimeout
+	case "gemini":
+		return config.Config.GeminiTimeout
+	case "shotstack":
+		return config.Config.ShotstackTimeout
+	}
+	return 0
+}
+
+// transient tells whether err is worth retrying: rate limits, server errors,
+// network errors and timeouts of a single attempt
+func transient(ctx context.Context, err error) bool {
+	if ctx.Err() != nil {
+		return false
+	}
+	var status *StatusError
+	if errors.As(err, &status) {
+		return retryableStatus(status.StatusCode)
+	}
+	var gerr *googleapi.Error
+	if errors.As(err, &gerr) {
+		return retryableStatus(gerr.Code)
+	}
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+	var netErr net.Error
+	return errors.As(err, &netErr)
+}
+
+func retryableStatus(code int) bool {
+	return code == http.StatusRequestTimeout || code == http.StatusTooManyRequests || code >= 500
+}
+
+// backoff is the exponential delay before the attempt after attempt, with jitter
+func backoff(attempt int) time.Duration {
+	d := config.Config.RetryBackoff << attempt
+	if d <= 0 || d > config.Config.RetryMaxBackoff {
+		d = config.Config.RetryMaxBackoff
+	}
+	if d <= 0 {
+		return 0
+	}
+	return d/2 + time.Duration(rand.Int63n(int64(d/2)+1))
+}
+
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
+	}
+}
+
+// breaker opens after BreakerFailures transient failures in a row and lets a
+// single call through once BreakerCooldown passed
+type breaker struct {
+	mu        sync.Mutex
+	failures  int
+	openUntil time.Time
+	probing   bool
+}
+
+var (
+	breakersMu sync.Mutex
+	breakers   = make(map[string]*breaker)
+)
+
+func breakerOf(provider string) *breaker {
+	breakersMu.Lock()
+	defer breakersMu.Unlock()
+	b, ok := breakers[provider]
+	if !ok {
+		b = &breaker{}
+		breakers[provider] = b
+	}
+	return b
+}
+
+func (b *breaker) allow(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	threshold := config.Config.BreakerFailures
+	if threshold <= 0 || b.failures < threshold {
+		return true
+	}
+	if now.Before(b.openUntil) || b.probing {
+		return false
+	}
+	b.probing = true
+	return true
+}
+
+func (b *breaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures = 0
+	b.probing = false
+}
+
+func (b *breaker) abort() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.probing = false
+}
+
+// failure records a transient failure and tells whether it opened the circuit
+func (b *breaker) failure(now time.Time) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.failures++
+	b.probing = false
+	threshold := config.Config.BreakerFailures
+	if threshold <= 0 || b.failures < threshold {
+		return false
+	}
+	b.openUntil = now.Add(config.Config.BreakerCooldown)
+	return b.failures == threshold
+}
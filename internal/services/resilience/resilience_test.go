@@ -0,0 +1,214 @@
+package resilience
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// fastRetries makes retries quick and the breaker out of the way
+func fastRetries(t *testing.T) {
+	restore := []func(){
+		setConfig(&config.Config.RetryAttempts, 3),
+		setConfig(&config.Config.RetryBackoff, time.Millisecond),
+		setConfig(&config.Config.RetryMaxBackoff, time.Second),
+		setConfig(&config.Config.BreakerFailures, 0),
+	}
+	t.Cleanup(func() {
+		for _, r := range restore {
+			r()
+		}
+	})
+}
+
+// failing returns a call failing with the errors in order, then succeeding
+func failing(errs ...error) (func(ctx context.Context) error, *int) {
+	calls := 0
+	return func(ctx context.Context) error {
+		calls++
+		if calls <= len(errs) {
+			return errs[calls-1]
+		}
+		return nil
+	}, &calls
+}
+
+func TestDoRetriesTransientStatuses(t *testing.T) {
+	fastRetries(t)
+	for _, code := range []int{http.StatusRequestTimeout, http.StatusTooManyRequests, http.StatusInternalServerError, http.StatusBadGateway, http.StatusServiceUnavailable} {
+		fn, calls := failing(FromResponse(code, http.Header{}, "busy"))
+		if err := Do(context.Background(), t.Name(), fn); err != nil || *calls != 2 {
+			t.Errorf("status %d: Do = %v after %d calls, want success on the second", code, err, *calls)
+		}
+	}
+}
+
+func TestDoDoesNotRetryOtherErrors(t *testing.T) {
+	fastRetries(t)
+	for _, err := range []error{
+		FromResponse(http.StatusBadRequest, http.Header{}, "bad prompt"),
+		FromResponse(http.StatusUnauthorized, http.Header{}, "bad key"),
+		FromResponse(http.StatusNotFound, http.Header{}, "no model"),
+		errors.New("invalid reply"),
+	} {
+		fn, calls := failing(err, err, err)
+		got := Do(context.Background(), t.Name(), fn)
+		if !errors.Is(got, err) || errors.Is(got, ErrUnavailable) || *calls != 1 {
+			t.Errorf("%v: Do = %v after %d calls, want the error after one call", err, got, *calls)
+		}
+	}
+}
+
+func TestDoGivesUp(t *testing.T) {
+	fastRetries(t)
+	status := FromResponse(http.StatusServiceUnavailable, http.Header{}, "down")
+	fn, calls := failing(status, status, status, status)
+	err := Do(context.Background(), t.Name(), fn)
+	if !errors.Is(err, ErrUnavailable) || !errors.Is(err, status) || *calls != 3 {
+		t.Errorf("Do = %v after %d calls, want ErrUnavailable after 3", err, *calls)
+	}
+}
+
+func TestDoHonoursRetryAfter(t *testing.T) {
+	fastRetries(t)
+	header := http.Header{}
+	header.Set("Retry-After", "1")
+	fn, calls := failing(FromResponse(http.StatusTooManyRequests, header, "slow down"))
+	start := time.Now()
+	if err := Do(context.Background(), t.Name(), fn); err != nil || *calls != 2 {
+		t.Fatalf("Do = %v after %d calls", err, *calls)
+	}
+	if waited := time.Since(start); waited < time.Second {
+		t.Errorf("waited %v, want the second of Retry-After", waited)
+	}
+}
+
+func TestDoCapsRetryAfter(t *testing.T) {
+	fastRetries(t)
+	defer setConfig(&config.Config.RetryMaxBackoff, 100*time.Millisecond)()
+	header := http.Header{}
+	header.Set("Retry-After", "3600")
+	fn, calls := failing(FromResponse(http.StatusTooManyRequests, header, "slow down"))
+	start := time.Now()
+	err := Do(context.Background(), t.Name(), fn)
+	if !errors.Is(err, ErrUnavailable) || *calls != 1 {
+		t.Errorf("Do = %v after %d calls, want ErrUnavailable without waiting", err, *calls)
+	}
+	if waited := time.Since(start); waited > time.Second {
+		t.Errorf("waited %v for a Retry-After above RetryMaxBackoff", waited)
+	}
+}
+
+func TestRetryAfter(t *testing.T) {
+	tests := map[string]time.Duration{
+		"":     0,
+		"5":    5 * time.Second,
+		"0":    0,
+		"-1":   0,
+		"soon": 0,
+	}
+	for value, want := range tests {
+		header := http.Header{}
+		header.Set("Retry-After", value)
+		if got := retryAfter(header); got != want {
+			t.Errorf("retryAfter(%q) = %v, want %v", value, got, want)
+		}
+	}
+	header := http.Header{}
+	header.Set("Retry-After", time.Now().Add(time.Minute).UTC().Format(http.TimeFormat))
+	if got := retryAfter(header); got < 58*time.Second || got > time.Minute {
+		t.Errorf("retryAfter of a date a minute away = %v", got)
+	}
+}
+
+func TestBreaker(t *testing.T) {
+	fastRetries(t)
+	defer setConfig(&config.Config.RetryAttempts, 1)()
+	defer setConfig(&config.Config.BreakerFailures, 2)()
+	defer setConfig(&config.Config.BreakerCooldown, 50*time.Millisecond)()
+	provider := t.Name()
+	down := func(ctx context.Context) error { return FromResponse(http.StatusBadGateway, http.Header{}, "down") }
+	calls := 0
+	up := func(ctx context.Context) error { calls++; return nil }
+
+	for i := 0; i < 2; i++ {
+		if err := Do(context.Background(), provider, down); errors.Is(err, ErrCircuitOpen) {
+			t.Fatalf("failure %d: circuit open before the threshold", i+1)
+		}
+	}
+	if err := Do(context.Background(), provider, up); !errors.Is(err, ErrCircuitOpen) || !errors.Is(err, ErrUnavailable) || calls != 0 {
+		t.Fatalf("Do = %v after %d calls, want the circuit open without calling", err, calls)
+	}
+
+	// A failed probe after the cooldown opens the circuit again
+	time.Sleep(60 * time.Millisecond)
+	if err := Do(context.Background(), provider, down); errors.Is(err, ErrCircuitOpen) {
+		t.Fatalf("probe was not let through: %v", err)
+	}
+	if err := Do(context.Background(), provider, up); !errors.Is(err, ErrCircuitOpen) || calls != 0 {
+		t.Fatalf("Do = %v after a failed probe, want the circuit open again", err)
+	}
+
+	// A successful probe closes it
+	time.Sleep(60 * time.Millisecond)
+	for i := 0; i < 3; i++ {
+		if err := Do(context.Background(), provider, up); err != nil {
+			t.Fatalf("call %d after a successful probe: %v", i+1, err)
+		}
+	}
+	if calls != 3 {
+		t.Errorf("%d calls made, want 3", calls)
+	}
+}
+
+func TestBreakerLetsOneProbeThrough(t *testing.T) {
+	defer setConfig(&config.Config.BreakerFailures, 1)()
+	defer setConfig(&config.Config.BreakerCooldown, time.Minute)()
+	b := &breaker{}
+	now := time.Now()
+	if !b.failure(now) {
+		t.Error("first failure did not open the circuit at threshold 1")
+	}
+	if b.allow(now.Add(30 * time.Second)) {
+		t.Error("call allowed during the cooldown")
+	}
+	later := now.Add(2 * time.Minute)
+	if !b.allow(later) {
+		t.Fatal("probe not allowed after the cooldown")
+	}
+	if b.allow(later) {
+		t.Error("second call allowed while probing")
+	}
+	// A cancelled probe says nothing about the provider, the next call probes again
+	b.abort()
+	if !b.allow(later) {
+		t.Error("no probe after a cancelled one")
+	}
+}
+
+func TestDoCancelled(t *testing.T) {
+	fastRetries(t)
+	defer setConfig(&config.Config.BreakerFailures, 1)()
+	ctx, cancel := context.WithCancel(context.Background())
+	err := Do(ctx, t.Name(), func(ctx context.Context) error {
+		cancel()
+		return fmt.Errorf("request: %w", ctx.Err())
+	})
+	if !errors.Is(err, context.Canceled) || errors.Is(err, ErrUnavailable) {
+		t.Errorf("Do = %v, want context.Canceled", err)
+	}
+	if err = Do(context.Background(), t.Name(), func(ctx context.Context) error { return nil }); err != nil {
+		t.Errorf("cancelled call opened the circuit: %v", err)
+	}
+}
+
+// setConfig sets a config value for a test and returns a func restoring it
+func setConfig[T any](field *T, value T) func() {
+	old := *field
+	*field = value
+	return func() { *field = old }
+}
@@ -2,9 +2,12 @@ package shotstack
 
 import (
 	"bytes"
+	"context"
 	"encoding/json"
 	"errors"
 	"fmt"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
+	"io"
 	"log"
 	"math/rand"
 	"net/http"
@@ -12,6 +15,17 @@ import (
 	"time"
 )
 
+var (
+	ErrRenderFailed  = errors.New("failed to render video")
+	ErrRenderTimeout = errors.New("timed out waiting for video render")
+)
+
+const (
+	// renderTimeout is how long GetUrl waits for a render to finish
+	renderTimeout = 10 * time.Minute
+	pollInterval  = 25 * time.Second
+)
+
 type Data struct {
 	Timeline Timeline `json:"timeline"`
 	Output   struct {
@@ -106,7 +120,7 @@ type Track struct {
 	Clips []Clip `json:"clips"`
 }
 
-func GenerateVideo(request Data) (string, error) {
+func GenerateVideo(ctx context.Context, request Data) (string, error) {
 	requestJson, err := json.Marshal(request)
 	if err != nil {
 		return "", err
@@ -117,90 +131,130 @@ func GenerateVideo(request Data) (string, error) {
 		return "", errors.New("SHOTSTACK_API_KEY not set in environment")
 	}
 
-	req, err := http.NewRequest("POST", "https://api.shotstack.io/edit/stage/render", bytes.NewBuffer(requestJson))
-	if err != nil {
-		return "", err
-	}
+	// Queueing the render is retried on rate limits and server errors
+	var result map[string]interface{}
+	err = resilience.Do(ctx, "shotstack", func(ctx context.Context) error {
+		req, err := http.NewRequestWithContext(ctx, "POST", "https://api.shotstack.io/edit/stage/render", bytes.NewBuffer(requestJson))
+		if err != nil {
+			return err
+		}
 
-	req.Header.Set("Content-Type", "application/json")
-	req.Header.Set("x-api-key", apiKey)
+		req.Header.Set("Content-Type", "application/json")
+		req.Header.Set("x-api-key", apiKey)
 
-	client := &http.Client{}
-	resp, err := client.Do(req)
-	if err != nil {
-		return "", err
-	}
-	defer resp.Body.Close()
+		client := &http.Client{}
+		resp, err := client.Do(req)
+		if err != nil {
+			return err
+		}
+		defer resp.Body.Close()
 
-	var result map[string]interface{}
-	if err = json.NewDecoder(resp.Body).Decode(&result); err != nil {
+		body, err := io.ReadAll(resp.Body)
+		if err != nil {
+			return err
+		}
+		if err = resilience.FromResponse(resp.StatusCode, resp.Header, string(body)); err != nil {
+			return err
+		}
+		return json.Unmarshal(body, &result)
+	})
+	if errors.Is(err, resilience.ErrUnavailable) || ctx.Err() != nil {
 		return "", err
 	}
+	if err != nil {
+		return "", fmt.Errorf("%w: failed to queue render: %v", ErrRenderFailed, err)
+	}
 
 	if success, ok := result["success"].(bool); !ok || !success {
 		log.Println(success)
-		return "", errors.New("failed to queue render: " + result["message"].(string))
+		return "", fmt.Errorf("%w: failed to queue render: %v", ErrRenderFailed, result["message"])
 	}
 
 	responseData, ok := result["response"].(map[string]interface{})
 	if !ok {
-		return "", errors.New("invalid response format")
+		return "", fmt.Errorf("%w: invalid response format", ErrRenderFailed)
 	}
 
 	renderID, ok := responseData["id"].(string)
 	if !ok {
 		log.Println(responseData)
-		return "", errors.New("render ID not found or not a string")
+		return "", fmt.Errorf("%w: render ID not found or not a string", ErrRenderFailed)
 	}
 
 	return renderID, nil
-
-	return renderID, nil
 }
 
-func GetUrl(id string) (string, error) {
+func GetUrl(ctx context.Context, id string) (string, error) {
+	deadline := time.Now().Add(renderTimeout)
+	client := &http.Client{Timeout: 10 * time.Second}
 	for {
-		url := fmt.Sprintf("https://api.shotstack.io/edit/stage/render/%s", id)
-		req, err := http.NewRequest("GET", url, nil)
+		url, done, err := poll(ctx, client, id)
 		if err != nil {
-			log.Fatal("Error creating request:", err)
+			return "", err
 		}
+		if done {
+			return url, nil
+		}
+		if time.Now().Add(pollInterval).After(deadline) {
+			return "", fmt.Errorf("%w: render %s", ErrRenderTimeout, id)
+		}
+		if err = sleep(ctx, pollInterval); err != nil {
+			return "", err
+		}
+	}
+}
 
-		req.Header.Set("x-api-key", os.Getenv("SHOTSTACK_API_KEY"))
+// poll asks Shotstack for the render once, done is false while the video is not ready yet
+func poll(ctx context.Context, client *http.Client, id string) (string, bool, error) {
+	url := fmt.Sprintf("https://api.shotstack.io/edit/stage/render/%s", id)
+	req, err := http.NewRequestWithContext(ctx, "GET", url, nil)
+	if err != nil {
+		return "", false, fmt.Errorf("%w: failed to create request: %v", ErrRenderFailed, err)
+	}
 
-		client := &http.Client{Timeout: 10 * time.Second}
-		resp, err := client.Do(req)
-		if err != nil {
-			log.Fatal("Error sending request:", err)
-		}
+	req.Header.Set("x-api-key", os.Getenv("SHOTSTACK_API_KEY"))
 
-		var response map[string]interface{}
-		err = json.NewDecoder(resp.Body).Decode(&response)
-		if err != nil {
-			log.Fatal("Error decoding response body:", err)
+	resp, err := client.Do(req)
+	if err != nil {
+		if ctx.Err() != nil {
+			return "", false, ctx.Err()
 		}
+		// Network errors are retried with the next poll
+		log.Println("Error sending request:", err)
+		return "", false, nil
+	}
+	defer resp.Body.Close()
 
-		log.Println(response)
+	var response map[string]interface{}
+	err = json.NewDecoder(resp.Body).Decode(&response)
+	if err != nil {
+		return "", false, fmt.Errorf("%w: failed to decode response body: %v", ErrRenderFailed, err)
+	}
 
-		success, ok := response["success"].(bool)
-		if !ok {
-			log.Fatal("Invalid response format, 'success' field not found or not a boolean")
-		}
+	log.Println(response)
 
-		if success {
-			url, ok = response["response"].(map[string]interface{})["url"].(string)
-			if !ok {
-				time.Sleep(25 * time.Second)
-			} else {
-				resp.Body.Close()
-				return url, nil
-			}
+	success, ok := response["success"].(bool)
+	if !ok {
+		return "", false, fmt.Errorf("%w: 'success' field not found or not a boolean", ErrRenderFailed)
+	}
+	if !success {
+		fmt.Println("Render was not successful. Retrying in 25 seconds...")
+		return "", false, nil
+	}
 
-		} else {
-			fmt.Println("Render was not successful. Retrying in 5 seconds...")
-			time.Sleep(25 * time.Second)
-		}
+	data, _ := response["response"].(map[string]interface{})
+	if status, _ := data["status"].(string); status == "failed" {
+		return "", false, fmt.Errorf("%w: render %s: %v", ErrRenderFailed, id, data["error"])
+	}
+	url, ok = data["url"].(string)
+	return url, ok, nil
+}
 
-		resp.Body.Close()
+func sleep(ctx context.Context, d time.Duration) error {
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-time.After(d):
+		return nil
 	}
 }
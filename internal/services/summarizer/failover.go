@@ -0,0 +1,54 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
+	"github.com/rendizi/stay-connected-inst/pkg/logger"
+	"go.uber.org/zap"
+)
+
+// failover calls secondary when primary is unavailable, that is when it kept
+// failing after retries or its circuit is open
+type failover struct {
+	primary   Summarizer
+	secondary Summarizer
+}
+
+func Failover(primary Summarizer, secondary Summarizer) Summarizer {
+	return failover{primary: primary, secondary: secondary}
+}
+
+func unavailable(err error) bool {
+	if errors.Is(err, resilience.ErrUnavailable) {
+		logger.Error("Failing over to the secondary summarizer", zap.Error(err))
+		return true
+	}
+	return false
+}
+
+func (f failover) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	story, err := f.primary.SummarizeImage(ctx, url, prompt)
+	if unavailable(err) {
+		return f.secondary.SummarizeImage(ctx, url, prompt)
+	}
+	return story, err
+}
+
+func (f failover) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	story, err := f.primary.SummarizeVideo(ctx, url, prompt)
+	if unavailable(err) {
+		return f.secondary.SummarizeVideo(ctx, url, prompt)
+	}
+	return story, err
+}
+
+func (f failover) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	summary, err := f.primary.SummarizeToOne(ctx, stories, busines, preferences)
+	if unavailable(err) {
+		return f.secondary.SummarizeToOne(ctx, stories, busines, preferences)
+	}
+	return summary, err
+}
@@ -0,0 +1,72 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"github.com/rendizi/stay-connected-inst/internal/services/resilience"
+	"net/http"
+	"testing"
+	"time"
+)
+
+// provider answers every call with status through resilience.Do
+type provider struct {
+	status int
+	calls  int
+}
+
+func (p *provider) do(ctx context.Context) error {
+	return resilience.Do(ctx, "failover-test", func(ctx context.Context) error {
+		p.calls++
+		return resilience.FromResponse(p.status, http.Header{}, http.StatusText(p.status))
+	})
+}
+
+func (p *provider) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return reply.Story{Description: "primary"}, p.do(ctx)
+}
+
+func (p *provider) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return reply.Story{Description: "primary"}, p.do(ctx)
+}
+
+func (p *provider) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	return "primary", p.do(ctx)
+}
+
+func TestFailover(t *testing.T) {
+	attempts, backoff, failures := config.Config.RetryAttempts, config.Config.RetryBackoff, config.Config.BreakerFailures
+	defer func() {
+		config.Config.RetryAttempts, config.Config.RetryBackoff, config.Config.BreakerFailures = attempts, backoff, failures
+	}()
+	config.Config.RetryAttempts, config.Config.RetryBackoff, config.Config.BreakerFailures = 2, time.Millisecond, 0
+	ctx := context.Background()
+	want, _ := Fake{}.SummarizeImage(ctx, "https://cdn.example.com/1.jpg", "")
+
+	// An unavailable primary is replaced by the secondary
+	primary := &provider{status: http.StatusServiceUnavailable}
+	s := Failover(primary, Fake{})
+	story, err := s.SummarizeImage(ctx, "https://cdn.example.com/1.jpg", "")
+	if err != nil || story != want {
+		t.Errorf("SummarizeImage = %+v, %v, want the story of the secondary", story, err)
+	}
+	if primary.calls != 2 {
+		t.Errorf("primary called %d times, want 2 attempts", primary.calls)
+	}
+	if summary, err := s.SummarizeToOne(ctx, []openai.StoriesType{{Author: "alice", Summarize: "hiking"}}, false, ""); err != nil || summary != "hiking" {
+		t.Errorf("SummarizeToOne = %q, %v, want the summary of the secondary", summary, err)
+	}
+
+	// Other errors are returned, the secondary would fail the same way
+	primary = &provider{status: http.StatusBadRequest}
+	s = Failover(primary, Fake{})
+	if _, err = s.SummarizeVideo(ctx, "https://cdn.example.com/2.mp4", ""); err == nil || errors.Is(err, resilience.ErrUnavailable) {
+		t.Errorf("SummarizeVideo = %v, want the error of the primary", err)
+	}
+	if primary.calls != 1 {
+		t.Errorf("primary called %d times, want 1", primary.calls)
+	}
+}
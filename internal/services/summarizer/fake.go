@@ -0,0 +1,53 @@
+package summarizer
+
+import (
+	"context"
+	"crypto/sha1"
+	"encoding/hex"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+	"strings"
+)
+
+// Fake is a deterministic summarizer that never leaves the process. The same
+// url always gives the same result, so it can be used in tests and local runs.
+type Fake struct{}
+
+func (Fake) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return fakeSummarize(ctx, "image", url)
+}
+
+func (Fake) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return fakeSummarize(ctx, "video", url)
+}
+
+func (Fake) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	if err := ctx.Err(); err != nil {
+		return "", err
+	}
+	if len(stories) == 0 {
+		return "Nothing interesting", nil
+	}
+	summarizes := make([]string, 0, len(stories))
+	for _, story := range stories {
+		summarizes = append(summarizes, story.Summarize)
+	}
+	return strings.Join(summarizes, " "), nil
+}
+
+func fakeSummarize(ctx context.Context, kind string, url string) (reply.Story, error) {
+	if err := ctx.Err(); err != nil {
+		return reply.Story{}, err
+	}
+	sum := sha1.Sum([]byte(url))
+	story := reply.Story{
+		Description: "Fake " + kind + " summary " + hex.EncodeToString(sum[:4]),
+		Relevance:   float64(sum[2]) / 255,
+		AddIt:       sum[0]%2 == 0,
+	}
+	story.Interesting = story.Relevance >= 0.25
+	if story.AddIt {
+		story.ClipLength = 2 + int(sum[1]%4)
+	}
+	return story, nil
+}
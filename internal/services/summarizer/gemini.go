@@ -0,0 +1,27 @@
+package summarizer
+
+import (
+	"context"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/internal/services/gemini"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+)
+
+type Gemini struct{}
+
+func (Gemini) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return gemini.SummarizeImage(ctx, url, prompt)
+}
+
+func (Gemini) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return gemini.SummarizeVideo(ctx, url, prompt)
+}
+
+func (Gemini) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	prompt, err := openai.MergePrompt(busines, preferences)
+	if err != nil {
+		return "", err
+	}
+	return gemini.SummarizeText(ctx, prompt, fmt.Sprintf("%s", stories))
+}
@@ -0,0 +1,59 @@
+package summarizer
+
+import (
+	"context"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+)
+
+// limited lets at most cap(slots) calls of the wrapped summarizer run at the same time
+type limited struct {
+	summarizer Summarizer
+	slots      chan struct{}
+}
+
+// Limit returns s with at most n calls in flight, callers wait for a free slot
+// until their ctx is done. n <= 0 leaves s unlimited.
+func Limit(s Summarizer, n int) Summarizer {
+	if n <= 0 {
+		return s
+	}
+	return limited{summarizer: s, slots: make(chan struct{}, n)}
+}
+
+func (l limited) acquire(ctx context.Context) error {
+	select {
+	case l.slots <- struct{}{}:
+		return nil
+	case <-ctx.Done():
+		return ctx.Err()
+	}
+}
+
+func (l limited) release() {
+	<-l.slots
+}
+
+func (l limited) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	if err := l.acquire(ctx); err != nil {
+		return reply.Story{}, err
+	}
+	defer l.release()
+	return l.summarizer.SummarizeImage(ctx, url, prompt)
+}
+
+func (l limited) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	if err := l.acquire(ctx); err != nil {
+		return reply.Story{}, err
+	}
+	defer l.release()
+	return l.summarizer.SummarizeVideo(ctx, url, prompt)
+}
+
+func (l limited) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	if err := l.acquire(ctx); err != nil {
+		return "", err
+	}
+	defer l.release()
+	return l.summarizer.SummarizeToOne(ctx, stories, busines, preferences)
+}
@@ -0,0 +1,22 @@
+package summarizer
+
+import (
+	"context"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+)
+
+type OpenAI struct{}
+
+func (OpenAI) SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return openai.SummarizeImage(ctx, url, prompt)
+}
+
+// SummarizeVideo is not supported, gpt-4o does not accept video input
+func (OpenAI) SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error) {
+	return reply.Story{}, ErrUnsupported
+}
+
+func (OpenAI) SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error) {
+	return openai.SummarizeImagesToOne(ctx, stories, busines, preferences)
+}
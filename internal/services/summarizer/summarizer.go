@@ -0,0 +1,89 @@
+package summarizer
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/services/openai"
+	"github.com/rendizi/stay-connected-inst/internal/services/reply"
+)
+
+var ErrUnsupported = errors.New("operation is not supported by this summarizer")
+
+// Summarizer turns story medias into short descriptions
+type Summarizer interface {
+	SummarizeImage(ctx context.Context, url string, prompt string) (reply.Story, error)
+	SummarizeVideo(ctx context.Context, url string, prompt string) (reply.Story, error)
+	SummarizeToOne(ctx context.Context, stories []openai.StoriesType, busines bool, preferences string) (string, error)
+}
+
+func New(name string) (Summarizer, error) {
+	switch name {
+	case "openai":
+		return OpenAI{}, nil
+	case "gemini":
+		return Gemini{}, nil
+	case "fake":
+		return Fake{}, nil
+	}
+	return nil, fmt.Errorf("unknown summarizer: %q", name)
+}
+
+// FromConfig returns summarizers for images, videos and merging, in that order.
+// Summarizers of the same provider share its concurrency limit, a configured
+// fallback takes over when the provider is unavailable.
+func FromConfig() (Summarizer, Summarizer, Summarizer, error) {
+	providers := make(map[string]Summarizer)
+	get := func(name string) (Summarizer, error) {
+		if s, ok := providers[name]; ok {
+			return s, nil
+		}
+		s, err := New(name)
+		if err != nil {
+			return nil, err
+		}
+		s = Limit(s, concurrency(name))
+		providers[name] = s
+		return s, nil
+	}
+	withFallback := func(name string, fallback string) (Summarizer, error) {
+		s, err := get(name)
+		if err != nil || fallback == "" || fallback == name {
+			return s, err
+		}
+		secondary, err := get(fallback)
+		if err != nil {
+			return nil, fmt.Errorf("fallback: %w", err)
+		}
+		return Failover(s, secondary), nil
+	}
+
+	images, err := withFallback(config.Config.ImageSummarizer, config.Config.ImageFallback)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("image summarizer: %w", err)
+	}
+	if config.Config.VideoSummarizer == "openai" || config.Config.VideoFallback == "openai" {
+		return nil, nil, nil, fmt.Errorf("video summarizer: openai: %w", ErrUnsupported)
+	}
+	videos, err := withFallback(config.Config.VideoSummarizer, config.Config.VideoFallback)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("video summarizer: %w", err)
+	}
+	merge, err := withFallback(config.Config.MergeSummarizer, config.Config.MergeFallback)
+	if err != nil {
+		return nil, nil, nil, fmt.Errorf("merge summarizer: %w", err)
+	}
+	return images, videos, merge, nil
+}
+
+// concurrency returns how many calls to the provider may run at the same time, 0 is unlimited
+func concurrency(name string) int {
+	switch name {
+	case "openai":
+		return config.Config.OpenAIConcurrency
+	case "gemini":
+		return config.Config.GeminiConcurrency
+	}
+	return 0
+}
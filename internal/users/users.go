@@ -0,0 +1,298 @@
+package users
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
+	"net/mail"
+	"strconv"
+	"strings"
+	"time"
+)
+
+var (
+	ErrUnknownPlan = errors.New("unknown plan")
+	ErrInvalidUser = errors.New("invalid user")
+)
+
+const (
+	// Usage counters are kept a day longer than their period
+	usageTTL = 48 * time.Hour
+	// ledgerSize is how many ledger entries are kept per user
+	ledgerSize = 1000
+)
+
+// Delivery channel types
+const (
+	Webhook  = "webhook"
+	Email    = "email"
+	Telegram = "telegram"
+)
+
+// Channel is where daily digests of a user are delivered. Target is the
+// webhook URL, the email address or the Telegram chat ID, Secret signs webhooks.
+type Channel struct {
+	Type   string `json:"type"`
+	Target string `json:"target"`
+	Secret string `json:"secret,omitempty"`
+}
+
+// User is a customer of the service with the Instagram usernames they track.
+// Daily users get a digest every day at DigestAt in their Timezone.
+type User struct {
+	ID          string    `json:"id"`
+	Usernames   []string  `json:"usernames"`
+	Preferences string    `json:"preferences"`
+	Plan        string    `json:"plan"`
+	Daily       bool      `json:"daily"`
+	Timezone    string    `json:"timezone"`
+	DigestAt    string    `json:"digestAt"`
+	Channels    []Channel `json:"channels"`
+	CreatedAt   time.Time `json:"createdAt"`
+	UpdatedAt   time.Time `json:"updatedAt"`
+}
+
+// Location returns the time zone of the user, UTC when none is set
+func (u *User) Location() (*time.Location, error) {
+	if u.Timezone == "" {
+		return time.UTC, nil
+	}
+	return time.LoadLocation(u.Timezone)
+}
+
+// DigestTime returns hour and minute of the daily digest in the time zone of the user
+func (u *User) DigestTime() (int, int, error) {
+	at := u.DigestAt
+	if at == "" {
+		at = config.Config.DailyDigestAt
+	}
+	t, err := time.Parse("15:04", at)
+	if err != nil {
+		return 0, 0, fmt.Errorf("invalid digest time %q, expected HH:MM", at)
+	}
+	return t.Hour(), t.Minute(), nil
+}
+
+// Plan limits how many units a user can spend per day and how many days of
+// history they can read
+type Plan struct {
+	Name        string
+	DailyUnits  float32
+	HistoryDays int
+}
+
+// Plans parses config.Config.Plans, a list like "free:10,pro:100:90". The
+// optional third field is days of history, config.Config.HistoryDays by default.
+func Plans() (map[string]Plan, error) {
+	plans := make(map[string]Plan)
+	for _, entry := range strings.Split(config.Config.Plans, ",") {
+		entry = strings.TrimSpace(entry)
+		if entry == "" {
+			continue
+		}
+		parts := strings.Split(entry, ":")
+		if len(parts) < 2 || len(parts) > 3 {
+			return nil, fmt.Errorf("invalid plan %q, expected name:units[:days]", entry)
+		}
+		name := parts[0]
+		daily, err := strconv.ParseFloat(parts[1], 32)
+		if err != nil {
+			return nil, fmt.Errorf("invalid units of plan %q: %v", name, err)
+		}
+		days := config.Config.HistoryDays
+		if len(parts) == 3 {
+			if days, err = strconv.Atoi(parts[2]); err != nil || days < 1 {
+				return nil, fmt.Errorf("invalid history days of plan %q", name)
+			}
+		}
+		plans[name] = Plan{Name: name, DailyUnits: float32(daily), HistoryDays: days}
+	}
+	return plans, nil
+}
+
+// HistoryDays is the longest history of any plan, history is kept that long
+// for every username
+func HistoryDays() int {
+	days := config.Config.HistoryDays
+	plans, err := Plans()
+	if err != nil {
+		return days
+	}
+	for _, plan := range plans {
+		if plan.HistoryDays > days {
+			days = plan.HistoryDays
+		}
+	}
+	return days
+}
+
+// Tracks reports whether user tracks username
+func (u *User) Tracks(username string) bool {
+	for _, tracked := range u.Usernames {
+		if tracked == username {
+			return true
+		}
+	}
+	return false
+}
+
+func PlanOf(name string) (Plan, error) {
+	plans, err := Plans()
+	if err != nil {
+		return Plan{}, err
+	}
+	plan, ok := plans[name]
+	if !ok {
+		return Plan{}, fmt.Errorf("%w: %q", ErrUnknownPlan, name)
+	}
+	return plan, nil
+}
+
+// Save stores user, a user without a plan gets the default one
+// validEmail reports whether target is a bare email address, it goes into the
+// headers of digest emails as it is
+func validEmail(target string) bool {
+	if strings.ContainsAny(target, "\r\n") {
+		return false
+	}
+	address, err := mail.ParseAddress(target)
+	return err == nil && address.Address == target
+}
+
+func Save(ctx context.Context, user *User) error {
+	if user.ID == "" {
+		return errors.New("user id is required")
+	}
+	if user.Plan == "" {
+		user.Plan = config.Config.DefaultPlan
+	}
+	if _, err := PlanOf(user.Plan); err != nil {
+		return err
+	}
+	if _, err := user.Location(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	if _, _, err := user.DigestTime(); err != nil {
+		return fmt.Errorf("%w: %v", ErrInvalidUser, err)
+	}
+	for _, channel := range user.Channels {
+		if channel.Type != Webhook && channel.Type != Email && channel.Type != Telegram {
+			return fmt.Errorf("%w: unknown channel %q", ErrInvalidUser, channel.Type)
+		}
+		if channel.Target == "" {
+			return fmt.Errorf("%w: %s channel has no target", ErrInvalidUser, channel.Type)
+		}
+		if channel.Type == Email && !validEmail(channel.Target) {
+			return fmt.Errorf("%w: invalid email address %q", ErrInvalidUser, channel.Target)
+		}
+	}
+	now := time.Now()
+	if user.CreatedAt.IsZero() {
+		user.CreatedAt = now
+	}
+	user.UpdatedAt = now
+	value, err := json.Marshal(user)
+	if err != nil {
+		return fmt.Errorf("failed to marshal user %s: %v", user.ID, err)
+	}
+	return redis.StoreUser(ctx, user.ID, string(value))
+}
+
+// IDs returns IDs of all users
+func IDs(ctx context.Context) ([]string, error) {
+	return redis.GetUserIDs(ctx)
+}
+
+func Get(ctx context.Context, id string) (*User, error) {
+	value, err := redis.GetUser(ctx, id)
+	if err != nil {
+		return nil, err
+	}
+	var user User
+	if err = json.Unmarshal([]byte(value), &user); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal user %s: %v", id, err)
+	}
+	return &user, nil
+}
+
+// period is the day usage is counted for
+func period(t time.Time) string {
+	return t.UTC().Format("2006-01-02")
+}
+
+// Usage returns the units user spent today and the daily limit of their plan
+func Usage(ctx context.Context, user *User) (float32, float32, error) {
+	plan, err := PlanOf(user.Plan)
+	if err != nil {
+		return 0, 0, err
+	}
+	used, err := redis.GetUsage(ctx, user.ID, period(time.Now()))
+	if err != nil {
+		return 0, 0, err
+	}
+	return float32(used), plan.DailyUnits, nil
+}
+
+// Entry is a single charge or refund in the ledger of a user
+type Entry struct {
+	JobID string    `json:"jobId"`
+	Units float32   `json:"units"`
+	At    time.Time `json:"at"`
+}
+
+// Entries returns the last count ledger entries of user id, oldest first
+func Entries(ctx context.Context, id string, count int64) ([]Entry, error) {
+	values, err := redis.GetLedger(ctx, id, count)
+	if err != nil {
+		return nil, err
+	}
+	entries := make([]Entry, 0, len(values))
+	for _, value := range values {
+		var entry Entry
+		if err = json.Unmarshal([]byte(value), &entry); err != nil {
+			continue
+		}
+		entries = append(entries, entry)
+	}
+	return entries, nil
+}
+
+// Ledger charges units spent by a job to its user. Every charge is checked
+// against the daily limit in Redis, so concurrent jobs of one user can not
+// spend more than the plan allows together.
+type Ledger struct {
+	userID string
+	jobID  string
+	period string
+	limit  float32
+}
+
+func NewLedger(user *User, jobID string) (*Ledger, error) {
+	plan, err := PlanOf(user.Plan)
+	if err != nil {
+		return nil, err
+	}
+	return &Ledger{userID: user.ID, jobID: jobID, period: period(time.Now()), limit: plan.DailyUnits}, nil
+}
+
+// Reserve charges units, it fails with redis.ErrQuotaExceeded over the daily limit
+func (l *Ledger) Reserve(units float32) error {
+	return l.charge(units)
+}
+
+// Refund gives back units of a reservation
+func (l *Ledger) Refund(units float32) error {
+	return l.charge(-units)
+}
+
+func (l *Ledger) charge(units float32) error {
+	entry, err := json.Marshal(Entry{JobID: l.jobID, Units: units, At: time.Now()})
+	if err != nil {
+		return fmt.Errorf("failed to marshal ledger entry: %v", err)
+	}
+	_, err = redis.ChargeUsage(context.Background(), l.userID, l.period, float64(units), float64(l.limit), string(entry), usageTTL, ledgerSize)
+	return err
+}
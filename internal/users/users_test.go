@@ -0,0 +1,25 @@
+package users
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+)
+
+func TestSaveValidatesEmail(t *testing.T) {
+	for _, target := range []string{
+		"alice",
+		"Alice <alice@example.com>",
+		"alice@example.com\r\nBcc: mallory@example.com",
+		"alice@example.com\n",
+	} {
+		user := &User{ID: "user", Channels: []Channel{{Type: Email, Target: target}}}
+		if err := Save(context.Background(), user); !errors.Is(err, ErrInvalidUser) || !strings.Contains(err.Error(), "email") {
+			t.Errorf("Save with email %q = %v, want ErrInvalidUser", target, err)
+		}
+	}
+	if !validEmail("alice@example.com") {
+		t.Error("alice@example.com is not valid")
+	}
+}
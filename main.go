@@ -1,18 +1,69 @@
 package main
 
 import (
+	"context"
 	"fmt"
+	"github.com/rendizi/stay-connected-inst/config"
+	"github.com/rendizi/stay-connected-inst/internal/auth"
+	"github.com/rendizi/stay-connected-inst/internal/daily"
 	grpc2 "github.com/rendizi/stay-connected-inst/internal/grpc"
+	"github.com/rendizi/stay-connected-inst/internal/inst"
+	"github.com/rendizi/stay-connected-inst/internal/prompts"
+	"github.com/rendizi/stay-connected-inst/internal/redis"
 	server2 "github.com/rendizi/stay-connected-inst/internal/server"
+	"github.com/rendizi/stay-connected-inst/internal/users"
 	"google.golang.org/grpc"
 	"log"
 	"net"
+	"net/http"
+	"os"
+	"os/signal"
+	"syscall"
 )
 
 func main() {
-	grpcServer := grpc.NewServer()
+	if err := auth.Check(); err != nil {
+		log.Fatalf("Invalid authentication config: %v", err)
+	}
+	options, err := auth.ServerOptions()
+	if err != nil {
+		log.Fatalf("Invalid TLS config: %v", err)
+	}
+	grpcServer := grpc.NewServer(options...)
+	if err = redis.Ping(context.Background()); err != nil {
+		log.Fatal(err)
+	}
 
-	grpc2.RegisterStoriesSummarizerServer(grpcServer, &server2.Server{})
+	server, err := server2.New()
+	if err != nil {
+		log.Fatalf("Failed to create server: %v", err)
+	}
+	if _, err = users.Plans(); err != nil {
+		log.Fatalf("Invalid plans: %v", err)
+	}
+	if err = prompts.Load(config.Config.PromptsDir); err != nil {
+		log.Fatalf("Invalid prompts: %v", err)
+	}
+	log.Printf("Prompts: %v", prompts.Versions())
+	go reloadPrompts()
+	grpc2.RegisterStoriesSummarizerServer(grpcServer, server)
+	grpc2.RegisterUsersAdminServer(grpcServer, &server2.UsersAdmin{})
+	server.ResumeActive(context.Background())
+	if config.Config.FeedAddress != "" && (config.Config.FeedSecret != "" || config.Config.Renderer == "ffmpeg") {
+		go func() {
+			if err := http.ListenAndServe(config.Config.FeedAddress, server2.FeedHandler()); err != nil {
+				log.Fatalf("Failed to serve feeds: %v", err)
+			}
+		}()
+	}
+	if config.Config.DailyDigests {
+		runner := &daily.Runner{Server: server}
+		go runner.Run(context.Background())
+	}
+	if server.Accounts != nil {
+		grpc2.RegisterAccountsAdminServer(grpcServer, &server2.Admin{Accounts: server.Accounts})
+		go inst.RefreshSessions(context.Background(), server.Accounts, config.Config.SessionRefresh)
+	}
 
 	// Listen on port 50051
 	listener, err := net.Listen("tcp", ":50051")
@@ -26,3 +77,16 @@ func main() {
 		log.Fatalf("Failed to serve: %v", err)
 	}
 }
+
+// reloadPrompts loads the prompts again on SIGHUP, invalid ones are logged and the old ones kept
+func reloadPrompts() {
+	signals := make(chan os.Signal, 1)
+	signal.Notify(signals, syscall.SIGHUP)
+	for range signals {
+		if err := prompts.Load(config.Config.PromptsDir); err != nil {
+			log.Printf("Failed to reload prompts: %v", err)
+			continue
+		}
+		log.Printf("Reloaded prompts: %v", prompts.Versions())
+	}
+}